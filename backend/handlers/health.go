@@ -3,6 +3,7 @@ package handlers
 import (
 	"time"
 
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,3 +15,23 @@ func HealthCheck(c *gin.Context) {
 		"timestamp": time.Now().Unix(),
 	})
 }
+
+// NewHealthCheck returns a health handler that also reports the headless
+// browser. The service is degraded while the browser is disconnected, as
+// pages then fall back to static fetching. A browser that is starting or being
+// replaced, as after a recycle, is not.
+func NewHealthCheck(browserStats func() services.BrowserPoolStats) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		browser := browserStats()
+		status := "healthy"
+		if browser.State == services.BrowserDisconnected {
+			status = "degraded"
+		}
+		c.JSON(200, gin.H{
+			"status":    status,
+			"service":   "web-scraper-backend",
+			"timestamp": time.Now().Unix(),
+			"browser":   browser,
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/Michael-Obele/web-scraper-backend/src/api"
 	"github.com/Michael-Obele/web-scraper-backend/src/config"
 	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/Michael-Obele/web-scraper-backend/src/storage"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/kpechenenko/rword"
@@ -25,11 +26,21 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Open the result store, falling back to memory if the database is unavailable
+	resultStore := openResultStore(cfg)
+	defer resultStore.Close()
+
 	// Initialize services
 	scraperService := services.NewScraperService(cfg)
 	defer scraperService.Close() // Ensure Chromedp is closed on exit
+	scraperService.SetResultStore(resultStore)
+
+	jobService := services.NewJobService(cfg, scraperService)
 
 	scrapeHandler := api.NewScrapeHandler(scraperService)
+	captureHandler := api.NewCaptureHandler(scraperService)
+	jobHandler := api.NewJobHandler(jobService)
+	historyHandler := api.NewHistoryHandler(resultStore)
 
 	// Initialize rword generator once at startup (fallback to nil on error)
 	var wordGen rword.GenerateRandom
@@ -92,8 +103,18 @@ func main() {
 		}
 		c.String(http.StatusOK, phrases[rand.Intn(len(phrases))])
 	})
-	router.GET("/health", handlers.HealthCheck)
+	router.GET("/health", handlers.NewHealthCheck(scraperService.BrowserStats))
 	router.GET("/scrape", scrapeHandler.HandleScrape)
+	router.POST("/scrape", scrapeHandler.HandleScrapePost)
+	router.GET("/scrape/stream", scrapeHandler.HandleScrapeStream)
+	router.GET("/screenshot", captureHandler.HandleScreenshot)
+	router.GET("/pdf", captureHandler.HandlePDF)
+	router.POST("/jobs", jobHandler.HandleCreateJob)
+	router.GET("/jobs/:id", jobHandler.HandleGetJob)
+	router.DELETE("/jobs/:id", jobHandler.HandleCancelJob)
+	router.GET("/history", historyHandler.HandleHistory)
+	router.GET("/results/:id", historyHandler.HandleGetResult)
+	router.GET("/results/:id/har", historyHandler.HandleGetResultHAR)
 
 	// Global handler for unknown routes - log and return a 404 response
 	router.NoRoute(func(c *gin.Context) {
@@ -121,17 +142,41 @@ func main() {
 	<-quit
 	log.Println("Shutting down server...")
 
-	// The context is used to inform the server it has 5 seconds to finish
-	// the requests it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// In-flight requests get the same grace period as scrape jobs; a timeout
+	// is logged rather than fatal so the job drain and deferred cleanups still run
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.GetShutdownTimeout())
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	// Let in-flight scrape jobs finish before Chromedp is closed
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), cfg.GetShutdownTimeout())
+	defer drainCancel()
+	if err := jobService.Shutdown(drainCtx); err != nil {
+		log.Printf("Scrape jobs cancelled during shutdown: %v", err)
 	}
 
 	log.Println("Server exiting")
 }
 
+// openResultStore opens the configured result database. SCRAPER_DB_PATH=:memory:
+// or an open failure keeps results in memory only.
+func openResultStore(cfg *config.Config) storage.ResultStore {
+	if cfg.DBPath == ":memory:" {
+		log.Println("Result store: in-memory")
+		return storage.NewMemoryStore(cfg.DBMaxEntries)
+	}
+
+	store, err := storage.NewBoltStore(cfg.DBPath, cfg.DBMaxEntries)
+	if err != nil {
+		log.Printf("Result store unavailable, keeping results in memory: %v", err)
+		return storage.NewMemoryStore(cfg.DBMaxEntries)
+	}
+	log.Printf("Result store: %s", cfg.DBPath)
+	return store
+}
+
 // helper to convert []string to []interface{} for fmt.Sprintf
 func interfaceSlice(ss []string) []interface{} {
 	out := make([]interface{}, len(ss))
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CaptureHandler serves page captures rendered in the headless browser
+type CaptureHandler struct {
+	scraperService *services.ScraperService
+}
+
+// NewCaptureHandler creates a new capture handler
+func NewCaptureHandler(scraperService *services.ScraperService) *CaptureHandler {
+	return &CaptureHandler{
+		scraperService: scraperService,
+	}
+}
+
+// HandleScreenshot handles GET /screenshot?url={url}&format={png|jpeg}&quality={n}&fullPage={bool}&selector={css}&width={n}&height={n}
+// and responds with the image bytes
+func (h *CaptureHandler) HandleScreenshot(c *gin.Context) {
+	targetURL := c.Query("url")
+	if !validateTargetURL(c, targetURL) {
+		return
+	}
+	opts, ok := bindScreenshotQuery(c)
+	if !ok {
+		return
+	}
+
+	shot, err := h.scraperService.Screenshot(c.Request.Context(), targetURL, opts)
+	if err != nil {
+		respondScrapeError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, shot.Format.ContentType(), shot.Data)
+}
+
+// HandlePDF handles GET /pdf?url={url}&paper={size}&landscape={bool}&background={bool}&margin={in}...
+// and responds with the printed PDF
+func (h *CaptureHandler) HandlePDF(c *gin.Context) {
+	targetURL := c.Query("url")
+	if !validateTargetURL(c, targetURL) {
+		return
+	}
+	opts, ok := bindPDFQuery(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.scraperService.PDF(c.Request.Context(), targetURL, opts)
+	if err != nil {
+		respondScrapeError(c, err)
+		return
+	}
+
+	filename := "page.pdf"
+	if parsed, err := url.Parse(targetURL); err == nil && parsed.Hostname() != "" {
+		filename = parsed.Hostname() + ".pdf"
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// bindScreenshotQuery reads screenshot options from the query string.
+// It responds with a 400 and returns false when they are invalid.
+func bindScreenshotQuery(c *gin.Context) (models.ScreenshotOptions, bool) {
+	opts := models.ScreenshotOptions{
+		Format:   models.ImageFormat(c.Query("format")),
+		Selector: c.Query("selector"),
+	}
+
+	if fullPage := c.Query("fullPage"); fullPage != "" {
+		parsed, err := strconv.ParseBool(fullPage)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid_screenshot", "fullPage must be true or false")
+			return opts, false
+		}
+		opts.FullPage = parsed
+	}
+
+	for name, target := range map[string]*int{"quality": &opts.Quality, "width": &opts.Width, "height": &opts.Height} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			RespondWithError(c, http.StatusBadRequest, "invalid_screenshot", name+" must be a positive integer")
+			return opts, false
+		}
+		*target = parsed
+	}
+
+	return opts, validateScreenshotOptions(c, opts)
+}
+
+// bindPDFQuery reads PDF options from the query string. margin sets all four
+// margins, and marginTop, marginBottom, marginLeft and marginRight override it.
+// It responds with a 400 and returns false when they are invalid.
+func bindPDFQuery(c *gin.Context) (models.PDFOptions, bool) {
+	opts := models.PDFOptions{
+		Paper:          models.PaperSize(c.Query("paper")),
+		HeaderTemplate: c.Query("headerTemplate"),
+		FooterTemplate: c.Query("footerTemplate"),
+	}
+
+	for name, target := range map[string]*bool{"landscape": &opts.Landscape, "background": &opts.Background} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid_pdf", name+" must be true or false")
+			return opts, false
+		}
+		*target = parsed
+	}
+
+	lengths := map[string]*float64{"width": &opts.Width, "height": &opts.Height}
+	margins := map[string]**float64{
+		"marginTop":    &opts.MarginTop,
+		"marginBottom": &opts.MarginBottom,
+		"marginLeft":   &opts.MarginLeft,
+		"marginRight":  &opts.MarginRight,
+	}
+	parseLength := func(name string) (float64, bool, bool) {
+		value := c.Query(name)
+		if value == "" {
+			return 0, false, true
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+			RespondWithError(c, http.StatusBadRequest, "invalid_pdf", name+" must be a number of inches")
+			return 0, false, false
+		}
+		return parsed, true, true
+	}
+
+	for name, target := range lengths {
+		value, set, ok := parseLength(name)
+		if !ok {
+			return opts, false
+		}
+		if set {
+			*target = value
+		}
+	}
+	margin, marginSet, ok := parseLength("margin")
+	if !ok {
+		return opts, false
+	}
+	for name, target := range margins {
+		value, set, ok := parseLength(name)
+		if !ok {
+			return opts, false
+		}
+		switch {
+		case set:
+			*target = &value
+		case marginSet:
+			*target = &margin
+		}
+	}
+
+	return opts, validatePDFOptions(c, opts)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/storage"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// HistoryHandler serves previously stored scrape results
+type HistoryHandler struct {
+	store storage.ResultStore
+}
+
+// NewHistoryHandler creates a new history handler
+func NewHistoryHandler(store storage.ResultStore) *HistoryHandler {
+	return &HistoryHandler{
+		store: store,
+	}
+}
+
+// HandleHistory handles GET /history?url=...&limit=...
+func (h *HistoryHandler) HandleHistory(c *gin.Context) {
+	targetURL := c.Query("url")
+	if !validateTargetURL(c, targetURL) {
+		return
+	}
+
+	limit := defaultHistoryLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			RespondWithError(c, http.StatusBadRequest, "bad_request", "limit must be a positive integer")
+			return
+		}
+		limit = min(n, maxHistoryLimit)
+	}
+
+	entries, err := h.store.History(c.Request.Context(), targetURL, limit)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "storage_failed", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"url":     targetURL,
+		"results": entries,
+	})
+}
+
+// HandleGetResult handles GET /results/{id}
+func (h *HistoryHandler) HandleGetResult(c *gin.Context) {
+	record, err := h.store.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, storage.ErrNotFound) {
+		RespondWithError(c, http.StatusNotFound, "result_not_found", err.Error())
+		return
+	}
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "storage_failed", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// HandleGetResultHAR handles GET /results/{id}/har, downloading the HAR
+// recorded with a stored result
+func (h *HistoryHandler) HandleGetResultHAR(c *gin.Context) {
+	id := c.Param("id")
+	har, err := h.store.HAR(c.Request.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		RespondWithError(c, http.StatusNotFound, "result_not_found", err.Error())
+		return
+	}
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "storage_failed", err.Error())
+		return
+	}
+	if har == nil {
+		RespondWithError(c, http.StatusNotFound, "har_not_found", "No HAR was recorded for this result; scrape with har=true")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.har"`, id))
+	c.JSON(http.StatusOK, har)
+}
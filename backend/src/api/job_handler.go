@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+// JobHandler handles asynchronous scrape job requests
+type JobHandler struct {
+	jobService *services.JobService
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(jobService *services.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// HandleCreateJob handles POST /jobs with a JSON ScrapeRequest body
+func (h *JobHandler) HandleCreateJob(c *gin.Context) {
+	var req models.ScrapeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "bad_request", "Request body must be a JSON scrape request")
+		return
+	}
+	if !validateScrapeRequest(c, req) {
+		return
+	}
+
+	job, err := h.jobService.Submit(req)
+	if err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// HandleGetJob handles GET /jobs/{id}
+func (h *JobHandler) HandleGetJob(c *gin.Context) {
+	job, err := h.jobService.Get(c.Param("id"))
+	if err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// HandleCancelJob handles DELETE /jobs/{id}
+func (h *JobHandler) HandleCancelJob(c *gin.Context) {
+	job, err := h.jobService.Cancel(c.Param("id"))
+	if err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// respondJobError maps job service errors to API error responses
+func respondJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrJobNotFound):
+		RespondWithError(c, http.StatusNotFound, "job_not_found", err.Error())
+	case errors.Is(err, services.ErrJobQueueFull), errors.Is(err, services.ErrJobServiceClosed):
+		RespondWithError(c, http.StatusServiceUnavailable, "job_rejected", err.Error())
+	default:
+		RespondWithError(c, http.StatusInternalServerError, "job_failed", err.Error())
+	}
+}
@@ -1,10 +1,10 @@
 package api
 
 import (
+	"io"
 	"net/http"
-	"net/url"
-	"strconv"
 
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
 	"github.com/Michael-Obele/web-scraper-backend/src/services"
 	"github.com/gin-gonic/gin"
 )
@@ -21,40 +21,73 @@ func NewScrapeHandler(scraperService *services.ScraperService) *ScrapeHandler {
 	}
 }
 
-// HandleScrape handles GET /scrape?url={url}&depth={n}
+// HandleScrape handles GET /scrape?url={url}&depth={n}&maxPages={n}&mode={static|browser|auto}
 func (h *ScrapeHandler) HandleScrape(c *gin.Context) {
-	// Get URL parameter
-	targetURL := c.Query("url")
-	if targetURL == "" {
-		RespondWithError(c, http.StatusBadRequest, "bad_request", "URL parameter is required")
+	req, ok := bindScrapeQuery(c)
+	if !ok {
 		return
 	}
 
-	// Validate URL
-	parsedURL, err := url.ParseRequestURI(targetURL)
-	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
-		RespondWithError(c, http.StatusBadRequest, "invalid_url", "URL must be a valid HTTP or HTTPS URL")
+	// Perform scrape
+	result, err := h.scraperService.Scrape(c.Request.Context(), req.URL, req.ScrapeOptions)
+	if err != nil {
+		respondScrapeError(c, err)
 		return
 	}
 
-	// Get depth parameter (default 1)
-	depth := 1
-	if depthStr := c.Query("depth"); depthStr != "" {
-		parsedDepth, err := strconv.Atoi(depthStr)
-		if err != nil || parsedDepth < 1 {
-			RespondWithError(c, http.StatusBadRequest, "invalid_depth", "Depth must be a positive integer")
-			return
-		}
-		depth = parsedDepth
+	// Return result
+	c.JSON(http.StatusOK, result)
+}
+
+// HandleScrapePost handles POST /scrape with a JSON ScrapeRequest body. It
+// accepts everything GET /scrape does, plus an extraction schema in extract.
+func (h *ScrapeHandler) HandleScrapePost(c *gin.Context) {
+	var req models.ScrapeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "bad_request", "Request body must be a JSON scrape request")
+		return
+	}
+	if !validateScrapeRequest(c, req) {
+		return
 	}
 
-	// Perform scrape
-	result, err := h.scraperService.Scrape(c.Request.Context(), targetURL, depth)
+	result, err := h.scraperService.Scrape(c.Request.Context(), req.URL, req.ScrapeOptions)
 	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, "scrape_failed", err.Error())
+		respondScrapeError(c, err)
 		return
 	}
 
-	// Return result
 	c.JSON(http.StatusOK, result)
 }
+
+// HandleScrapeStream handles GET /scrape/stream with the same parameters as /scrape.
+// It streams scrape progress as Server-Sent Events, ending with a done or error event.
+func (h *ScrapeHandler) HandleScrapeStream(c *gin.Context) {
+	req, ok := bindScrapeQuery(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	events := make(chan models.ScrapeEvent, 16)
+	go func() {
+		defer close(events)
+		h.scraperService.ScrapeWithEvents(ctx, req.URL, req.ScrapeOptions, func(event models.ScrapeEvent) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Stream(func(w io.Writer) bool {
+		event, ok := <-events
+		if !ok {
+			return false
+		}
+		c.SSEvent(string(event.Type), event)
+		return true
+	})
+}
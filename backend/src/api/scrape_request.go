@@ -0,0 +1,418 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/extract"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxViewportSize caps screenshot viewport dimensions
+const maxViewportSize = 8192
+
+// maxPaperInches and maxMarginInches cap PDF paper and margin sizes
+const (
+	maxPaperInches  = 100
+	maxMarginInches = 10
+)
+
+// maxBlockRules caps the resource kinds, patterns and domains one request may block
+const maxBlockRules = 200
+
+// maxCaptureRules caps the URL patterns and content types one request may capture API responses by
+const maxCaptureRules = 50
+
+// Browser action limits: how many actions a request may script, how many
+// viewport heights one scroll may cover and how long one wait may last,
+// whether an action or the page load wait. The longest scroll, with its
+// 500ms pause per step and 10s of slack, fits in the longest wait.
+const (
+	maxBrowserActions = 50
+	maxScrollTimes    = 100
+	maxActionWaitMs   = 60000
+)
+
+// bindScrapeQuery reads scrape parameters from the query string.
+// It responds with a 400 and returns false when they are invalid.
+func bindScrapeQuery(c *gin.Context) (models.ScrapeRequest, bool) {
+	req := models.ScrapeRequest{URL: c.Query("url")}
+	if !validateTargetURL(c, req.URL) {
+		return req, false
+	}
+
+	// Get depth parameter (default 1)
+	req.Depth = 1
+	if depthStr := c.Query("depth"); depthStr != "" {
+		parsedDepth, err := strconv.Atoi(depthStr)
+		if err != nil || parsedDepth < 1 {
+			RespondWithError(c, http.StatusBadRequest, "invalid_depth", "Depth must be a positive integer")
+			return req, false
+		}
+		req.Depth = parsedDepth
+	}
+
+	// Get maxPages parameter (default: configured cap)
+	if maxPagesStr := c.Query("maxPages"); maxPagesStr != "" {
+		parsedMaxPages, err := strconv.Atoi(maxPagesStr)
+		if err != nil || parsedMaxPages < 1 {
+			RespondWithError(c, http.StatusBadRequest, "invalid_max_pages", "maxPages must be a positive integer")
+			return req, false
+		}
+		req.MaxPages = parsedMaxPages
+	}
+
+	// Get mode parameter (default: configured mode)
+	req.Mode = models.RenderMode(c.Query("mode"))
+
+	// Get cache parameter (default: prefer)
+	req.Cache = models.CachePolicy(c.Query("cache"))
+
+	// Get content parameter (default: configured content mode)
+	req.Content = models.ContentMode(c.Query("content"))
+
+	// Get wait parameters (default: configured wait)
+	wait, ok := bindWaitQuery(c)
+	if !ok {
+		return req, false
+	}
+	req.Wait = wait
+
+	// Get block parameters (default: configured blocking)
+	req.Block = bindBlockQuery(c)
+
+	// Get API capture parameters (default: none captured)
+	req.CaptureAPI = bindCaptureAPIQuery(c)
+
+	// Get har parameter (default: false)
+	if harStr := c.Query("har"); harStr != "" {
+		parsedHAR, err := strconv.ParseBool(harStr)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid_har", "har must be true or false")
+			return req, false
+		}
+		req.HAR = parsedHAR
+	}
+
+	return req, validateScrapeOptions(c, req.ScrapeOptions)
+}
+
+// bindWaitQuery reads the wait, waitMs, waitSelector, waitScript and
+// waitTimeoutMs parameters. It returns nil when none is given, and responds
+// with a 400 and returns false when a number is malformed.
+func bindWaitQuery(c *gin.Context) (*models.WaitOptions, bool) {
+	wait := models.WaitOptions{
+		Strategy: models.WaitStrategy(c.Query("wait")),
+		Selector: c.Query("waitSelector"),
+		Script:   c.Query("waitScript"),
+	}
+	given := wait.Strategy != "" || wait.Selector != "" || wait.Script != ""
+	for name, target := range map[string]*int{"waitMs": &wait.Ms, "waitTimeoutMs": &wait.TimeoutMs} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			RespondWithError(c, http.StatusBadRequest, "invalid_wait", name+" must be a positive integer")
+			return nil, false
+		}
+		*target = parsed
+		given = true
+	}
+	if !given {
+		return nil, true
+	}
+	return &wait, true
+}
+
+// bindBlockQuery reads the comma-separated block, blockPatterns and
+// blockDomains parameters. It returns nil when none is given.
+func bindBlockQuery(c *gin.Context) *models.BlockOptions {
+	var block models.BlockOptions
+	for _, kind := range splitQueryList(c.Query("block")) {
+		block.Resources = append(block.Resources, models.ResourceKind(kind))
+	}
+	block.Patterns = splitQueryList(c.Query("blockPatterns"))
+	block.Domains = splitQueryList(c.Query("blockDomains"))
+	if block.Empty() {
+		return nil
+	}
+	return &block
+}
+
+// bindCaptureAPIQuery reads the comma-separated captureApi (URL patterns) and
+// captureApiTypes parameters. It returns nil when neither is given.
+func bindCaptureAPIQuery(c *gin.Context) *models.APICaptureOptions {
+	patterns := splitQueryList(c.Query("captureApi"))
+	contentTypes := splitQueryList(c.Query("captureApiTypes"))
+	if len(patterns) == 0 && len(contentTypes) == 0 {
+		return nil
+	}
+	return &models.APICaptureOptions{Patterns: patterns, ContentTypes: contentTypes}
+}
+
+// splitQueryList splits a comma-separated query value, dropping blank items
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validateScrapeRequest checks a scrape request decoded from a JSON body.
+// It responds with a 400 and returns false when the request is invalid.
+func validateScrapeRequest(c *gin.Context, req models.ScrapeRequest) bool {
+	return validateTargetURL(c, req.URL) && validateScrapeOptions(c, req.ScrapeOptions)
+}
+
+// validateTargetURL checks that the target URL is present and uses HTTP or HTTPS
+func validateTargetURL(c *gin.Context, targetURL string) bool {
+	if targetURL == "" {
+		RespondWithError(c, http.StatusBadRequest, "bad_request", "URL parameter is required")
+		return false
+	}
+
+	// Validate URL
+	parsedURL, err := url.ParseRequestURI(targetURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		RespondWithError(c, http.StatusBadRequest, "invalid_url", "URL must be a valid HTTP or HTTPS URL")
+		return false
+	}
+
+	return true
+}
+
+// validateScrapeOptions checks the crawl options shared by every scrape endpoint
+func validateScrapeOptions(c *gin.Context, opts models.ScrapeOptions) bool {
+	if opts.Depth < 0 {
+		RespondWithError(c, http.StatusBadRequest, "invalid_depth", "Depth must be a positive integer")
+		return false
+	}
+	if opts.MaxPages < 0 {
+		RespondWithError(c, http.StatusBadRequest, "invalid_max_pages", "maxPages must be a positive integer")
+		return false
+	}
+	if opts.Mode != "" && !opts.Mode.Valid() {
+		RespondWithError(c, http.StatusBadRequest, "invalid_mode", "mode must be one of static, browser or auto")
+		return false
+	}
+	if opts.Cache != "" && !opts.Cache.Valid() {
+		RespondWithError(c, http.StatusBadRequest, "invalid_cache", "cache must be one of prefer, bypass or only")
+		return false
+	}
+	if opts.Content != "" && !opts.Content.Valid() {
+		RespondWithError(c, http.StatusBadRequest, "invalid_content", "content must be one of main or full")
+		return false
+	}
+	if opts.Screenshot != nil && !validateScreenshotOptions(c, *opts.Screenshot) {
+		return false
+	}
+	if len(opts.Actions) > 0 && !validateBrowserActions(c, opts.Actions) {
+		return false
+	}
+	if opts.Wait != nil && !validateWaitOptions(c, *opts.Wait) {
+		return false
+	}
+	if opts.Block != nil && !validateBlockOptions(c, *opts.Block) {
+		return false
+	}
+	if opts.CaptureAPI != nil && !validateCaptureAPIOptions(c, *opts.CaptureAPI) {
+		return false
+	}
+	if len(opts.Extract) > 0 {
+		if _, err := extract.CompileSchema(opts.Extract); err != nil {
+			RespondWithError(c, http.StatusBadRequest, "invalid_extract", "Invalid extraction schema: "+err.Error())
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateScreenshotOptions checks screenshot options
+func validateScreenshotOptions(c *gin.Context, opts models.ScreenshotOptions) bool {
+	var message string
+	switch {
+	case opts.Format != "" && !opts.Format.Valid():
+		message = "format must be png or jpeg"
+	case opts.Quality < 0 || opts.Quality > 100:
+		message = "quality must be between 1 and 100"
+	case opts.Quality > 0 && opts.Format != models.ImageJPEG:
+		message = "quality only applies to jpeg screenshots"
+	case opts.Width < 0 || opts.Width > maxViewportSize || opts.Height < 0 || opts.Height > maxViewportSize:
+		message = fmt.Sprintf("width and height must be between 1 and %d", maxViewportSize)
+	case opts.FullPage && opts.Selector != "":
+		message = "use either fullPage or selector, not both"
+	default:
+		return true
+	}
+	RespondWithError(c, http.StatusBadRequest, "invalid_screenshot", message)
+	return false
+}
+
+// validateBrowserActions checks that every action is known and carries the
+// fields it needs
+func validateBrowserActions(c *gin.Context, actions []models.BrowserAction) bool {
+	if len(actions) > maxBrowserActions {
+		RespondWithError(c, http.StatusBadRequest, "invalid_actions", fmt.Sprintf("at most %d actions are allowed", maxBrowserActions))
+		return false
+	}
+	for i, action := range actions {
+		var message string
+		switch {
+		case !action.Type.Valid():
+			message = "type must be one of click, type, press, scroll, waitForSelector, wait, select or evaluate"
+		case action.Selector == "" && (action.Type == models.ActionClick || action.Type == models.ActionTypeText ||
+			action.Type == models.ActionWaitForSelector || action.Type == models.ActionSelect):
+			message = fmt.Sprintf("%s needs a selector", action.Type)
+		case action.Type == models.ActionTypeText && action.Text == "":
+			message = "type needs text"
+		case action.Type == models.ActionPress && action.Key == "":
+			message = "press needs a key"
+		case action.Type == models.ActionPress && !validKey(action.Key):
+			message = fmt.Sprintf("unknown key %q", action.Key)
+		case action.Type == models.ActionSelect && action.Value == "":
+			message = "select needs a value"
+		case action.Type == models.ActionEvaluate && action.Script == "":
+			message = "evaluate needs a script"
+		case action.Type == models.ActionWait && (action.Ms < 1 || action.Ms > maxActionWaitMs):
+			message = fmt.Sprintf("wait needs ms between 1 and %d", maxActionWaitMs)
+		case action.Times < 0 || action.Times > maxScrollTimes:
+			message = fmt.Sprintf("times must be between 1 and %d", maxScrollTimes)
+		case action.TimeoutMs < 0 || action.TimeoutMs > maxActionWaitMs:
+			message = fmt.Sprintf("timeoutMs must be between 1 and %d", maxActionWaitMs)
+		default:
+			continue
+		}
+		RespondWithError(c, http.StatusBadRequest, "invalid_actions", fmt.Sprintf("action %d: %s", i+1, message))
+		return false
+	}
+	return true
+}
+
+// validateWaitOptions checks that the wait strategy, if given, is known and
+// carries the fields it needs. Without one the service infers it.
+func validateWaitOptions(c *gin.Context, wait models.WaitOptions) bool {
+	var message string
+	switch {
+	case wait.Strategy != "" && !wait.Strategy.Valid():
+		message = "strategy must be one of networkIdle, domStable, selector, function or delay"
+	case wait.Strategy == models.WaitSelector && wait.Selector == "":
+		message = "the selector strategy needs a selector"
+	case wait.Strategy == models.WaitFunction && wait.Script == "":
+		message = "the function strategy needs a script"
+	case wait.Ms < 0 || wait.Ms > maxActionWaitMs:
+		message = fmt.Sprintf("ms must be between 1 and %d", maxActionWaitMs)
+	case wait.TimeoutMs < 0 || wait.TimeoutMs > maxActionWaitMs:
+		message = fmt.Sprintf("timeoutMs must be between 1 and %d", maxActionWaitMs)
+	default:
+		return true
+	}
+	RespondWithError(c, http.StatusBadRequest, "invalid_wait", message)
+	return false
+}
+
+// validateBlockOptions checks resource kinds, patterns and domains to block
+func validateBlockOptions(c *gin.Context, block models.BlockOptions) bool {
+	var message string
+	switch {
+	case len(block.Resources)+len(block.Patterns)+len(block.Domains) > maxBlockRules:
+		message = fmt.Sprintf("at most %d resources, patterns and domains may be blocked", maxBlockRules)
+	case slices.ContainsFunc(block.Resources, func(kind models.ResourceKind) bool { return !kind.Valid() }):
+		message = "resources must be image, media, font or stylesheet"
+	case slices.Contains(block.Patterns, ""):
+		message = "patterns must not be empty"
+	case slices.ContainsFunc(block.Domains, func(domain string) bool { return domain == "" || strings.ContainsAny(domain, "/:*? ") }):
+		message = "domains must be bare host names such as ads.example.com"
+	default:
+		return true
+	}
+	RespondWithError(c, http.StatusBadRequest, "invalid_block", message)
+	return false
+}
+
+// validateCaptureAPIOptions checks the URL patterns and content types of API responses to capture
+func validateCaptureAPIOptions(c *gin.Context, capture models.APICaptureOptions) bool {
+	var message string
+	switch {
+	case len(capture.Patterns)+len(capture.ContentTypes) > maxCaptureRules:
+		message = fmt.Sprintf("at most %d patterns and content types are allowed", maxCaptureRules)
+	case slices.Contains(capture.Patterns, ""):
+		message = "patterns must not be empty"
+	case slices.ContainsFunc(capture.ContentTypes, func(contentType string) bool {
+		return !strings.Contains(contentType, "/") && !strings.Contains(contentType, "*")
+	}):
+		message = "contentTypes must be MIME types such as application/json"
+	default:
+		return true
+	}
+	RespondWithError(c, http.StatusBadRequest, "invalid_capture_api", message)
+	return false
+}
+
+// validKey reports whether a press action's key can be sent to the browser
+func validKey(key string) bool {
+	_, ok := services.KeyCode(key)
+	return ok
+}
+
+// validatePDFOptions checks print-to-PDF options
+func validatePDFOptions(c *gin.Context, opts models.PDFOptions) bool {
+	_, _, paperOK := opts.Paper.Dimensions()
+	var message string
+	switch {
+	case opts.Paper != "" && !paperOK:
+		message = "paper must be one of letter, legal, tabloid, ledger, a3, a4, a5 or a6"
+	case opts.Paper != "" && (opts.Width != 0 || opts.Height != 0):
+		message = "use either paper or width and height, not both"
+	case (opts.Width != 0) != (opts.Height != 0):
+		message = "width and height must be given together"
+	case opts.Width < 0 || opts.Width > maxPaperInches || opts.Height < 0 || opts.Height > maxPaperInches:
+		message = fmt.Sprintf("width and height must be between 0 and %d inches", maxPaperInches)
+	default:
+		for _, margin := range []*float64{opts.MarginTop, opts.MarginBottom, opts.MarginLeft, opts.MarginRight} {
+			if margin != nil && (*margin < 0 || *margin > maxMarginInches) {
+				message = fmt.Sprintf("margins must be between 0 and %d inches", maxMarginInches)
+			}
+		}
+		if message == "" {
+			return true
+		}
+	}
+	RespondWithError(c, http.StatusBadRequest, "invalid_pdf", message)
+	return false
+}
+
+// respondScrapeError maps scraper errors to API error responses
+func respondScrapeError(c *gin.Context, err error) {
+	var robotsErr *services.RobotsDisallowedError
+	if errors.As(err, &robotsErr) {
+		RespondWithError(c, http.StatusForbidden, "robots_disallowed", err.Error())
+		return
+	}
+	var blockedErr *services.BlockedTargetError
+	if errors.As(err, &blockedErr) {
+		RespondWithError(c, http.StatusForbidden, "blocked_target", err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrBrowserBusy) {
+		RespondWithError(c, http.StatusServiceUnavailable, "browser_busy", err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrCacheMiss) {
+		RespondWithError(c, http.StatusGatewayTimeout, "cache_miss", err.Error())
+		return
+	}
+	RespondWithError(c, http.StatusInternalServerError, "scrape_failed", err.Error())
+}
@@ -15,9 +15,48 @@ type Config struct {
 	// Scraper settings
 	ScraperDelaySeconds    int
 	ScraperUserAgents      []string
+	ScraperUAStrategy      string
 	ChromedpTimeoutSeconds int
+	ChromeWSURL            string
+	BrowserMaxTabs         int
+	BrowserQueueSeconds    int
+	BrowserRecycleAfter    int
+	BrowserHealthSeconds   int
 	ScraperTimeoutSeconds  int
 	IgnoreRobotsTxt        bool
+	RobotsCacheTTLSeconds  int
+	DefaultRenderMode      string
+	DefaultContentMode     string
+	DefaultWaitStrategy    string
+	DefaultWaitMs          int
+	WaitTimeoutMs          int
+
+	// Browser resource blocking settings
+	BlockResources []string
+	BlockPatterns  []string
+	BlockDomains   []string
+
+	// Target safety settings
+	AllowedCIDRs []string
+	DeniedCIDRs  []string
+
+	// Crawl settings
+	ScraperMaxDepth int
+	ScraperMaxPages int
+
+	// Job settings
+	JobWorkers             int
+	JobQueueSize           int
+	JobTTLSeconds          int
+	ShutdownTimeoutSeconds int
+
+	// Storage settings
+	DBPath       string
+	DBMaxEntries int
+
+	// Cache settings
+	CacheTTLSeconds int
+	CacheMaxEntries int
 }
 
 // Load reads configuration from environment variables with sensible defaults
@@ -25,14 +64,41 @@ func Load() *Config {
 	return &Config{
 		ServerPort:          getEnv("PORT", "8080"),
 		ScraperDelaySeconds: getEnvAsInt("SCRAPER_DELAY_S", 2),
-		ScraperUserAgents: getEnvAsSlice("SCRAPER_USER_AGENTS", []string{
+		ScraperUserAgents: getEnvAsUserAgents("SCRAPER_USER_AGENTS", []string{
 			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
 			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
 			"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
 		}),
+		ScraperUAStrategy:      getEnv("SCRAPER_UA_STRATEGY", "round-robin"),
 		ChromedpTimeoutSeconds: getEnvAsInt("CHROMEDP_TIMEOUT_S", 10),
+		ChromeWSURL:            getEnv("CHROME_WS_URL", ""),
+		BrowserMaxTabs:         getEnvAsInt("CHROMEDP_MAX_TABS", 4),
+		BrowserQueueSeconds:    getEnvAsInt("CHROMEDP_QUEUE_TIMEOUT_S", 30),
+		BrowserRecycleAfter:    getEnvAsInt("CHROMEDP_RECYCLE_AFTER", 0),
+		BrowserHealthSeconds:   getEnvAsInt("CHROMEDP_HEALTH_INTERVAL_S", 30),
 		ScraperTimeoutSeconds:  getEnvAsInt("SCRAPER_TIMEOUT_S", 30),
 		IgnoreRobotsTxt:        getEnvAsBool("SCRAPER_IGNORE_ROBOTS", false),
+		RobotsCacheTTLSeconds:  getEnvAsInt("SCRAPER_ROBOTS_TTL_S", 3600),
+		DefaultRenderMode:      getEnv("SCRAPER_DEFAULT_MODE", "auto"),
+		DefaultContentMode:     getEnv("SCRAPER_DEFAULT_CONTENT", "full"),
+		DefaultWaitStrategy:    getEnv("SCRAPER_WAIT_STRATEGY", "delay"),
+		DefaultWaitMs:          getEnvAsInt("SCRAPER_WAIT_MS", 0),
+		WaitTimeoutMs:          getEnvAsInt("SCRAPER_WAIT_TIMEOUT_MS", 5000),
+		BlockResources:         getEnvAsSlice("SCRAPER_BLOCK_RESOURCES", nil),
+		BlockPatterns:          getEnvAsSlice("SCRAPER_BLOCK_PATTERNS", nil),
+		BlockDomains:           getEnvAsSlice("SCRAPER_BLOCK_DOMAINS", nil),
+		AllowedCIDRs:           getEnvAsSlice("SCRAPER_ALLOW_CIDRS", nil),
+		DeniedCIDRs:            getEnvAsSlice("SCRAPER_DENY_CIDRS", nil),
+		ScraperMaxDepth:        getEnvAsInt("SCRAPER_MAX_DEPTH", 3),
+		ScraperMaxPages:        getEnvAsInt("SCRAPER_MAX_PAGES", 20),
+		JobWorkers:             getEnvAsInt("SCRAPER_JOB_WORKERS", 2),
+		JobQueueSize:           getEnvAsInt("SCRAPER_JOB_QUEUE_SIZE", 100),
+		JobTTLSeconds:          getEnvAsInt("SCRAPER_JOB_TTL_S", 3600),
+		ShutdownTimeoutSeconds: getEnvAsInt("SHUTDOWN_TIMEOUT_S", 30),
+		DBPath:                 getEnv("SCRAPER_DB_PATH", "data/scrapes.db"),
+		DBMaxEntries:           getEnvAsInt("SCRAPER_DB_MAX_ENTRIES", 1000),
+		CacheTTLSeconds:        getEnvAsInt("SCRAPER_CACHE_TTL_S", 300),
+		CacheMaxEntries:        getEnvAsInt("SCRAPER_CACHE_MAX_ENTRIES", 500),
 	}
 }
 
@@ -46,11 +112,41 @@ func (c *Config) GetChromedpTimeout() time.Duration {
 	return time.Duration(c.ChromedpTimeoutSeconds) * time.Second
 }
 
+// GetBrowserQueueTimeout returns how long a page may wait for a free browser tab as a time.Duration
+func (c *Config) GetBrowserQueueTimeout() time.Duration {
+	return time.Duration(c.BrowserQueueSeconds) * time.Second
+}
+
+// GetBrowserHealthInterval returns how often the browser is health-checked as a time.Duration
+func (c *Config) GetBrowserHealthInterval() time.Duration {
+	return time.Duration(c.BrowserHealthSeconds) * time.Second
+}
+
 // GetScraperTimeout returns the configured scraper timeout as a time.Duration
 func (c *Config) GetScraperTimeout() time.Duration {
 	return time.Duration(c.ScraperTimeoutSeconds) * time.Second
 }
 
+// GetRobotsCacheTTL returns how long robots.txt rules are cached as a time.Duration
+func (c *Config) GetRobotsCacheTTL() time.Duration {
+	return time.Duration(c.RobotsCacheTTLSeconds) * time.Second
+}
+
+// GetJobTTL returns how long finished jobs are kept as a time.Duration
+func (c *Config) GetJobTTL() time.Duration {
+	return time.Duration(c.JobTTLSeconds) * time.Second
+}
+
+// GetShutdownTimeout returns how long in-flight work may drain on shutdown as a time.Duration
+func (c *Config) GetShutdownTimeout() time.Duration {
+	return time.Duration(c.ShutdownTimeoutSeconds) * time.Second
+}
+
+// GetCacheTTL returns how long cached pages are served without revalidation as a time.Duration
+func (c *Config) GetCacheTTL() time.Duration {
+	return time.Duration(c.CacheTTLSeconds) * time.Second
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -81,3 +177,16 @@ func getEnvAsSlice(key string, defaultValue []string) []string {
 	}
 	return strings.Split(valueStr, ",")
 }
+
+// getEnvAsUserAgents splits a user agent list. Real user agents contain commas
+// ("KHTML, like Gecko"), so a "|" separator takes precedence when present.
+func getEnvAsUserAgents(key string, defaultValue []string) []string {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	if strings.Contains(valueStr, "|") {
+		return strings.Split(valueStr, "|")
+	}
+	return strings.Split(valueStr, ",")
+}
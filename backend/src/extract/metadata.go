@@ -0,0 +1,322 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/markdown"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// publishedNames and modifiedNames are meta names and properties that carry page dates, in order of preference
+var (
+	publishedNames = []string{"article:published_time", "og:published_time", "datepublished", "date", "pubdate", "publish-date", "publish_date", "dc.date", "dc.date.issued", "dcterms.created", "citation_publication_date"}
+	modifiedNames  = []string{"article:modified_time", "og:updated_time", "datemodified", "last-modified", "dcterms.modified"}
+)
+
+// vocabulary describes the attributes an embedded item syntax uses
+type vocabulary struct {
+	scope    string   // Attribute that starts an item
+	property string   // Attribute naming a property
+	types    string   // Attribute listing the item's types
+	ids      []string // Attributes holding the item's identifier, in order of preference
+	vocab    bool     // Whether relative types are expanded with the nearest vocab attribute
+}
+
+var (
+	microdata = vocabulary{scope: "itemscope", property: "itemprop", types: "itemtype", ids: []string{"itemid"}}
+	rdfa      = vocabulary{scope: "typeof", property: "property", types: "typeof", ids: []string{"resource", "about"}, vocab: true}
+)
+
+// Metadata reads the metadata a page declares about itself: meta and link
+// tags, OpenGraph and Twitter cards, JSON-LD blocks and microdata/RDFa items.
+// Relative URLs resolve against markdown.BaseURL(doc). Metadata is always
+// returned; the error reports JSON-LD blocks that could not be parsed.
+func Metadata(doc *goquery.Document) (*models.Metadata, error) {
+	base := markdown.BaseURL(doc)
+	meta := &models.Metadata{
+		Language: strings.TrimSpace(doc.Find("html").First().AttrOr("lang", "")),
+	}
+
+	tags := make(map[string]string)
+	doc.Find("meta[content]").Each(func(i int, sel *goquery.Selection) {
+		content := strings.TrimSpace(sel.AttrOr("content", ""))
+		if content == "" {
+			return
+		}
+		for _, attr := range []string{"property", "name", "http-equiv", "itemprop"} {
+			key := strings.ToLower(strings.TrimSpace(sel.AttrOr(attr, "")))
+			if key == "" {
+				continue
+			}
+			if _, seen := tags[key]; !seen {
+				tags[key] = content
+			}
+			switch {
+			case strings.HasPrefix(key, "og:"), strings.HasPrefix(key, "article:"):
+				meta.OpenGraph = addFirst(meta.OpenGraph, key, content)
+			case strings.HasPrefix(key, "twitter:"):
+				meta.Twitter = addFirst(meta.Twitter, key, content)
+			}
+			return
+		}
+	})
+
+	meta.Description = firstOf(tags, "description", "og:description", "twitter:description")
+	meta.Author = firstOf(tags, "author", "article:author", "dc.creator")
+	meta.Published = firstOf(tags, publishedNames...)
+	meta.Modified = firstOf(tags, modifiedNames...)
+	if meta.Language == "" {
+		meta.Language = tags["content-language"]
+	}
+	for _, keyword := range strings.Split(tags["keywords"], ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			meta.Keywords = append(meta.Keywords, keyword)
+		}
+	}
+	if href, ok := doc.Find("link[rel~=canonical][href]").First().Attr("href"); ok {
+		meta.Canonical = resolveURL(base, href)
+	}
+
+	var malformed int
+	var firstErr error
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, sel *goquery.Selection) {
+		items, err := parseJSONLD(sel.Text())
+		if err != nil {
+			malformed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		meta.JSONLD = append(meta.JSONLD, items...)
+	})
+	fillFromJSONLD(meta)
+
+	meta.Microdata = microdata.items(doc, base)
+	meta.RDFa = rdfa.items(doc, base)
+
+	if malformed > 0 {
+		return meta, fmt.Errorf("ignored %d malformed JSON-LD block(s): %w", malformed, firstErr)
+	}
+	return meta, nil
+}
+
+// parseJSONLD decodes a JSON-LD script body into its top-level objects,
+// flattening arrays and @graph containers
+func parseJSONLD(text string) ([]map[string]any, error) {
+	text = strings.TrimSpace(text)
+	for _, wrapper := range [][2]string{{"<!--", "-->"}, {"<![CDATA[", "]]>"}} {
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, wrapper[0]), wrapper[1]))
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	var data any
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		return nil, err
+	}
+	return flattenJSONLD(data, nil), nil
+}
+
+// flattenJSONLD lists the objects in a JSON-LD value. Objects taken out of a
+// @graph inherit the container's @context.
+func flattenJSONLD(value any, context any) []map[string]any {
+	switch v := value.(type) {
+	case []any:
+		var out []map[string]any
+		for _, element := range v {
+			out = append(out, flattenJSONLD(element, context)...)
+		}
+		return out
+	case map[string]any:
+		if ctx, ok := v["@context"]; ok {
+			context = ctx
+		}
+		if graph, ok := v["@graph"]; ok {
+			return flattenJSONLD(graph, context)
+		}
+		if _, ok := v["@context"]; !ok && context != nil {
+			v["@context"] = context
+		}
+		return []map[string]any{v}
+	}
+	return nil
+}
+
+// fillFromJSONLD fills the author, dates and description the meta tags left
+// empty from the first JSON-LD object that declares them
+func fillFromJSONLD(meta *models.Metadata) {
+	for _, item := range meta.JSONLD {
+		if meta.Author == "" {
+			meta.Author = jsonLDName(item["author"])
+		}
+		if meta.Published == "" {
+			meta.Published, _ = item["datePublished"].(string)
+		}
+		if meta.Modified == "" {
+			meta.Modified, _ = item["dateModified"].(string)
+		}
+		if meta.Description == "" {
+			meta.Description, _ = item["description"].(string)
+		}
+	}
+}
+
+// jsonLDName reads a name from a JSON-LD value that is a string, a Person or
+// Organization object, or a list of those (the first one wins)
+func jsonLDName(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		name, _ := v["name"].(string)
+		return name
+	case []any:
+		for _, element := range v {
+			if name := jsonLDName(element); name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// items returns the top-level items of the vocabulary in doc: items that are
+// not the value of another item's property
+func (v vocabulary) items(doc *goquery.Document, base *url.URL) []models.StructuredItem {
+	var out []models.StructuredItem
+	doc.Find("[" + v.scope + "]").Each(func(i int, sel *goquery.Selection) {
+		if _, nested := sel.Attr(v.property); nested && sel.Parent().Closest("["+v.scope+"]").Length() > 0 {
+			return
+		}
+		out = append(out, v.item(sel, base))
+	})
+	return out
+}
+
+// item reads an item and its properties
+func (v vocabulary) item(sel *goquery.Selection, base *url.URL) models.StructuredItem {
+	item := models.StructuredItem{
+		Type:       v.itemTypes(sel),
+		Properties: make(map[string][]any),
+	}
+	for _, attr := range v.ids {
+		if id := strings.TrimSpace(sel.AttrOr(attr, "")); id != "" {
+			item.ID = id
+			break
+		}
+	}
+	v.collect(sel, base, &item)
+	if len(item.Properties) == 0 {
+		item.Properties = nil
+	}
+	return item
+}
+
+// collect adds the properties below sel to item, stopping at nested items
+func (v vocabulary) collect(sel *goquery.Selection, base *url.URL, item *models.StructuredItem) {
+	sel.Children().Each(func(i int, child *goquery.Selection) {
+		_, scoped := child.Attr(v.scope)
+		if names := strings.Fields(child.AttrOr(v.property, "")); len(names) > 0 {
+			var value any
+			if scoped {
+				value = v.item(child, base)
+			} else {
+				value = propertyValue(child, base)
+			}
+			for _, name := range names {
+				item.Properties[name] = append(item.Properties[name], value)
+			}
+		}
+		if !scoped {
+			v.collect(child, base, item)
+		}
+	})
+}
+
+// itemTypes lists an item's types. Relative RDFa types are expanded with the nearest vocab.
+func (v vocabulary) itemTypes(sel *goquery.Selection) []string {
+	types := strings.Fields(sel.AttrOr(v.types, ""))
+	if !v.vocab {
+		return types
+	}
+	vocab := sel.Closest("[vocab]").AttrOr("vocab", "")
+	for i, t := range types {
+		if vocab != "" && !strings.Contains(t, ":") {
+			types[i] = vocab + t
+		}
+	}
+	return types
+}
+
+// propertyValue reads the value of a property element: an explicit content
+// attribute, a resolved URL for links and media, a machine-readable value, or its text
+func propertyValue(sel *goquery.Selection, base *url.URL) string {
+	if content, ok := sel.Attr("content"); ok {
+		return strings.TrimSpace(content)
+	}
+
+	var attr string
+	switch goquery.NodeName(sel) {
+	case "a", "area", "link":
+		attr = "href"
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		attr = "src"
+	case "object":
+		attr = "data"
+	case "data", "meter":
+		return strings.TrimSpace(sel.AttrOr("value", ""))
+	case "time":
+		if datetime, ok := sel.Attr("datetime"); ok {
+			return strings.TrimSpace(datetime)
+		}
+	}
+	if attr != "" {
+		if ref, ok := sel.Attr(attr); ok {
+			return resolveURL(base, ref)
+		}
+	}
+	if resource, ok := sel.Attr("resource"); ok {
+		return resolveURL(base, resource)
+	}
+	return strings.Join(strings.Fields(sel.Text()), " ")
+}
+
+// resolveURL makes ref absolute against base, leaving it unchanged when that is not possible
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if base == nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// addFirst records key in m unless it already has a value, allocating m if needed
+func addFirst(m map[string]string, key, value string) map[string]string {
+	if m == nil {
+		m = make(map[string]string)
+	}
+	if _, ok := m[key]; !ok {
+		m[key] = value
+	}
+	return m
+}
+
+// firstOf returns the first non-empty tag among keys
+func firstOf(tags map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := tags[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,218 @@
+// Package extract pulls structured information out of parsed HTML documents.
+package extract
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// minContentLength is the amount of text a candidate needs to count as the main content
+const minContentLength = 140
+
+// minParagraphLength is the amount of text a paragraph needs to contribute to scoring
+const minParagraphLength = 25
+
+// clutterSelectors match elements that are never part of the main content
+var clutterSelectors = strings.Join([]string{
+	"script", "style", "noscript", "template", "iframe", "svg", "form", "button",
+	"nav", "aside", "footer", "dialog",
+	"[role=navigation]", "[role=banner]", "[role=contentinfo]", "[role=complementary]", "[role=dialog]",
+	"[hidden]", "[aria-hidden=true]",
+}, ", ")
+
+var (
+	// unlikelyPattern matches class/id values of page furniture
+	unlikelyPattern = regexp.MustCompile(`(?i)-ad-|ad-break|agegate|banner|breadcrumb|combx|comment|community|consent|cookie|disqus|extra|footer|gdpr|header|legends|menu|modal|newsletter|pager|pagination|popup|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|supplemental`)
+	// maybeCandidatePattern rescues elements that match unlikelyPattern but may hold content
+	maybeCandidatePattern = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	// positivePattern and negativePattern adjust a candidate's score by its class/id
+	positivePattern = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativePattern = regexp.MustCompile(`(?i)hidden|banner|combx|comment|com-|contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget|nav|menu|cookie|consent|subscribe|newsletter|popup|modal`)
+	// displayNonePattern matches inline styles that hide an element
+	displayNonePattern = regexp.MustCompile(`(?i)display\s*:\s*none|visibility\s*:\s*hidden`)
+)
+
+// MainContent isolates the primary content of doc, readability-style: page
+// furniture is stripped, then block elements are scored by text and link
+// density (favouring <article> and <main>) and the best candidate is kept
+// along with related siblings. The returned document is a copy; doc is not
+// modified. ok is false when no candidate stands out, in which case callers
+// should fall back to the full page.
+func MainContent(doc *goquery.Document) (*goquery.Document, bool) {
+	clone := goquery.NewDocumentFromNode(doc.Selection.Clone().Get(0))
+	body := clone.Find("body").First()
+	if body.Length() == 0 {
+		return doc, false
+	}
+	removeClutter(body)
+
+	// A single semantic container is trusted outright
+	for _, selector := range []string{"main, [role=main]", "article"} {
+		semantic := body.Find(selector)
+		if semantic.Length() == 1 && textLength(semantic) >= minContentLength {
+			return wrapContent(doc, semantic), true
+		}
+	}
+
+	scores := scoreCandidates(body)
+	top := topCandidate(body, scores)
+	if top == nil {
+		return doc, false
+	}
+	topSel := clone.FindNodes(top)
+	if textLength(topSel) < minContentLength {
+		return doc, false
+	}
+
+	// Keep siblings that score well or read like body paragraphs
+	threshold := math.Max(10, scores[top]*0.2)
+	var keep []*html.Node
+	topSel.Parent().Children().Each(func(i int, sibling *goquery.Selection) {
+		node := sibling.Get(0)
+		switch {
+		case node == top:
+		case scores[node] >= threshold:
+		case sibling.Is("p") && linkDensity(sibling) < 0.25 && textLength(sibling) > 80:
+		default:
+			return
+		}
+		keep = append(keep, node)
+	})
+
+	return wrapContent(doc, clone.FindNodes(keep...)), true
+}
+
+// removeClutter deletes furniture, hidden elements and unlikely candidates
+func removeClutter(body *goquery.Selection) {
+	body.Find(clutterSelectors).Remove()
+	body.Find("[style]").FilterFunction(func(i int, sel *goquery.Selection) bool {
+		return displayNonePattern.MatchString(sel.AttrOr("style", ""))
+	}).Remove()
+
+	body.Find("*").FilterFunction(func(i int, sel *goquery.Selection) bool {
+		if sel.Is("article, main, table, tbody, thead, tr, td, th, a") {
+			return false
+		}
+		match := sel.AttrOr("class", "") + " " + sel.AttrOr("id", "")
+		return unlikelyPattern.MatchString(match) && !maybeCandidatePattern.MatchString(match)
+	}).Remove()
+}
+
+// scoreCandidates scores the ancestors of every paragraph-like element
+func scoreCandidates(body *goquery.Selection) map[*html.Node]float64 {
+	scores := make(map[*html.Node]float64)
+
+	body.Find("p, pre, td, blockquote, div").Each(func(i int, sel *goquery.Selection) {
+		// Divs only count when they hold text directly rather than other blocks
+		if sel.Is("div") && sel.Children().Filter("p, div, pre, blockquote, table, ul, ol, section, article").Length() > 0 {
+			return
+		}
+		text := strings.TrimSpace(sel.Text())
+		if len(text) < minParagraphLength {
+			return
+		}
+
+		contentScore := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text)/100), 3)
+
+		ancestor := sel.Parent()
+		for level := 0; level < 3 && ancestor.Length() > 0 && !ancestor.Is("html"); level++ {
+			node := ancestor.Get(0)
+			if _, ok := scores[node]; !ok {
+				scores[node] = initialScore(ancestor)
+			}
+			divider := 1.0
+			switch {
+			case level == 1:
+				divider = 2
+			case level > 1:
+				divider = float64(level * 3)
+			}
+			scores[node] += contentScore / divider
+			ancestor = ancestor.Parent()
+		}
+	})
+
+	// Link-heavy candidates are navigation, not content
+	for node, score := range scores {
+		scores[node] = score * (1 - linkDensity(body.FindNodes(node)))
+	}
+	return scores
+}
+
+// topCandidate returns the highest-scoring candidate, taking the first in
+// document order on a tie so the choice does not depend on map order
+func topCandidate(body *goquery.Selection, scores map[*html.Node]float64) *html.Node {
+	var top *html.Node
+	for _, node := range append(body.Nodes[:1:1], body.Find("*").Nodes...) {
+		score, ok := scores[node]
+		if ok && (top == nil || score > scores[top]) {
+			top = node
+		}
+	}
+	return top
+}
+
+// initialScore seeds a candidate by its tag and class/id
+func initialScore(sel *goquery.Selection) float64 {
+	var score float64
+	switch goquery.NodeName(sel) {
+	case "article", "main":
+		score = 25
+	case "div", "section":
+		score = 5
+	case "pre", "td", "blockquote":
+		score = 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score = -5
+	}
+
+	for _, attr := range []string{"class", "id"} {
+		value := sel.AttrOr(attr, "")
+		if value == "" {
+			continue
+		}
+		if negativePattern.MatchString(value) {
+			score -= 25
+		}
+		if positivePattern.MatchString(value) {
+			score += 25
+		}
+	}
+	return score
+}
+
+// linkDensity is the share of a selection's text that sits inside links
+func linkDensity(sel *goquery.Selection) float64 {
+	total := textLength(sel)
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	sel.Find("a").Each(func(i int, a *goquery.Selection) {
+		links += textLength(a)
+	})
+	return float64(links) / float64(total)
+}
+
+// textLength is the length of a selection's text with whitespace collapsed
+func textLength(sel *goquery.Selection) int {
+	return len(strings.Join(strings.Fields(sel.Text()), " "))
+}
+
+// wrapContent builds a new document whose body holds the given nodes. The
+// source document's URL and <base> are carried over so relative links still resolve.
+func wrapContent(source *goquery.Document, content *goquery.Selection) *goquery.Document {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader("<html><head></head><body></body></html>"))
+	doc.Url = source.Url
+	if base := source.Find("base[href]").First(); base.Length() > 0 {
+		doc.Find("head").AppendSelection(base.Clone())
+	}
+	doc.Find("body").AppendSelection(content)
+	return doc
+}
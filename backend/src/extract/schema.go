@@ -0,0 +1,343 @@
+package extract
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/markdown"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+)
+
+// numberPattern matches a number with optional sign, thousands separators and decimals
+var numberPattern = regexp.MustCompile(`[-+]?\d[\d,]*(\.\d+)?|[-+]?\.\d+`)
+
+// Schema is a compiled extraction schema, safe for concurrent use
+type Schema struct {
+	fields []field
+}
+
+// field is a compiled models.ExtractField
+type field struct {
+	name       string
+	css        goquery.Matcher
+	xpath      *xpath.Expr
+	scalar     string // XPath expression returning a number, string or boolean instead of nodes
+	attr       string
+	html       bool
+	list       bool
+	fields     []field
+	transforms []func(string) (string, bool)
+	number     bool
+}
+
+// CompileSchema checks an extraction schema and compiles its selectors and
+// patterns. Errors name the offending field by its dotted path.
+func CompileSchema(fields []models.ExtractField) (*Schema, error) {
+	compiled, err := compileFields(fields, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{fields: compiled}, nil
+}
+
+// compileFields compiles the fields of one object
+func compileFields(fields []models.ExtractField, prefix string) ([]field, error) {
+	seen := make(map[string]bool)
+	out := make([]field, 0, len(fields))
+	for _, spec := range fields {
+		path := prefix + spec.Name
+		if spec.Name == "" {
+			return nil, fmt.Errorf("field %q: name is required", path)
+		}
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("field %q: duplicate name", path)
+		}
+		seen[spec.Name] = true
+
+		f, err := compileField(spec, path)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// compileField compiles a single field; path names it in error messages
+func compileField(spec models.ExtractField, path string) (field, error) {
+	f := field{name: spec.Name, attr: spec.Attr, html: spec.HTML, list: spec.List}
+	fail := func(format string, args ...any) (field, error) {
+		return f, fmt.Errorf("field %q: "+format, append([]any{path}, args...)...)
+	}
+
+	switch {
+	case spec.Selector != "" && spec.XPath != "":
+		return fail("use either selector or xpath, not both")
+	case spec.Selector != "":
+		sel, err := cascadia.Compile(spec.Selector)
+		if err != nil {
+			return fail("invalid selector: %w", err)
+		}
+		f.css = sel
+	case spec.XPath != "":
+		expr, err := xpath.Compile(spec.XPath)
+		if err != nil {
+			return fail("invalid xpath: %w", err)
+		}
+		if !selectsNodes(expr) {
+			if spec.Attr != "" || spec.HTML || len(spec.Fields) > 0 {
+				return fail("xpath returns a value rather than nodes, so it cannot be combined with attr, html or fields")
+			}
+			f.scalar = spec.XPath
+			break
+		}
+		f.xpath = expr
+	}
+
+	if spec.Attr != "" && spec.HTML {
+		return fail("use either attr or html, not both")
+	}
+	if len(spec.Fields) > 0 {
+		if spec.Attr != "" || spec.HTML || len(spec.Transforms) > 0 {
+			return fail("nested fields cannot be combined with attr, html or transforms")
+		}
+		nested, err := compileFields(spec.Fields, path+".")
+		if err != nil {
+			return f, err
+		}
+		f.fields = nested
+		return f, nil
+	}
+
+	for i, t := range spec.Transforms {
+		if f.number {
+			return fail("the number transform must come last")
+		}
+		switch t.Type {
+		case models.TransformTrim:
+			f.transforms = append(f.transforms, func(s string) (string, bool) {
+				return strings.TrimSpace(s), true
+			})
+		case models.TransformRegex:
+			if t.Pattern == "" {
+				return fail("transform %d: regex requires a pattern", i+1)
+			}
+			re, err := regexp.Compile(t.Pattern)
+			if err != nil {
+				return fail("transform %d: invalid pattern: %w", i+1, err)
+			}
+			f.transforms = append(f.transforms, regexTransform(re))
+		case models.TransformNumber:
+			f.number = true
+		default:
+			return fail("transform %d: unknown type %q (want trim, regex or number)", i+1, t.Type)
+		}
+	}
+	return f, nil
+}
+
+// selectsNodes reports whether expr selects nodes rather than computing a
+// number, string or boolean, such as count(//li)
+func selectsNodes(expr *xpath.Expr) bool {
+	_, ok := expr.Evaluate(htmlquery.CreateXPathNavigator(&html.Node{Type: html.DocumentNode})).(*xpath.NodeIterator)
+	return ok
+}
+
+// regexTransform keeps the first capture group of re, or the whole match when it has none
+func regexTransform(re *regexp.Regexp) func(string) (string, bool) {
+	return func(s string) (string, bool) {
+		match := re.FindStringSubmatch(s)
+		if match == nil {
+			return "", false
+		}
+		if len(match) > 1 {
+			return match[1], true
+		}
+		return match[0], true
+	}
+}
+
+// Extract evaluates the schema against doc. Single fields without a match
+// are null and list fields without matches are empty arrays.
+func (s *Schema) Extract(doc *goquery.Document) map[string]any {
+	return extractFields(s.fields, doc.Selection, markdown.BaseURL(doc))
+}
+
+// extractFields builds the object for fields within scope
+func extractFields(fields []field, scope *goquery.Selection, base *url.URL) map[string]any {
+	out := make(map[string]any, len(fields))
+	for _, f := range fields {
+		if f.scalar != "" {
+			value := f.evaluate(scope)
+			if f.list {
+				values := []any{}
+				if value != nil {
+					values = append(values, value)
+				}
+				out[f.name] = values
+			} else {
+				out[f.name] = value
+			}
+			continue
+		}
+
+		matches := f.matches(scope)
+		if f.list {
+			values := make([]any, 0, len(matches))
+			for _, m := range matches {
+				if value := f.value(m, base); value != nil {
+					values = append(values, value)
+				}
+			}
+			out[f.name] = values
+			continue
+		}
+
+		out[f.name] = nil
+		if len(matches) > 0 {
+			out[f.name] = f.value(matches[0], base)
+		}
+	}
+	return out
+}
+
+// match is a node a field selected. XPath expressions can select an
+// attribute, which is read in place of the node's text.
+type match struct {
+	node *html.Node
+	attr string // Name of the selected attribute, if any
+}
+
+// matches returns the nodes the field selects within scope. Fields without
+// a selector or expression select the scope itself.
+func (f field) matches(scope *goquery.Selection) []match {
+	var nodes []match
+	switch {
+	case f.css != nil:
+		for _, node := range scope.FindMatcher(f.css).Nodes {
+			nodes = append(nodes, match{node: node})
+		}
+	case f.xpath != nil:
+		for _, node := range scope.Nodes {
+			iter := f.xpath.Select(htmlquery.CreateXPathNavigator(node))
+			for iter.MoveNext() {
+				nav := iter.Current().(*htmlquery.NodeNavigator)
+				m := match{node: nav.Current()}
+				if nav.NodeType() == xpath.AttributeNode {
+					m.attr = nav.LocalName()
+				}
+				nodes = append(nodes, m)
+			}
+		}
+	default:
+		for _, node := range scope.Nodes {
+			nodes = append(nodes, match{node: node})
+		}
+	}
+	return nodes
+}
+
+// evaluate computes a scalar XPath field within scope. Numbers and booleans
+// are kept as such unless transforms apply; NaN and infinities are null.
+func (f field) evaluate(scope *goquery.Selection) any {
+	if len(scope.Nodes) == 0 {
+		return nil
+	}
+	// Evaluating reuses the expression's state, so compile a copy per use to
+	// keep the schema safe for concurrent use
+	expr, err := xpath.Compile(f.scalar)
+	if err != nil {
+		return nil
+	}
+
+	var text string
+	switch value := expr.Evaluate(htmlquery.CreateXPathNavigator(scope.Nodes[0])).(type) {
+	case string:
+		text = value
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil
+		}
+		if len(f.transforms) == 0 {
+			return value
+		}
+		text = strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		if len(f.transforms) == 0 && !f.number {
+			return value
+		}
+		text = strconv.FormatBool(value)
+	default:
+		return nil
+	}
+	return f.transform(text)
+}
+
+// value reads the field from a match. It returns nil when the attribute is
+// missing or a transform rejects the value.
+func (f field) value(m match, base *url.URL) any {
+	sel := goquery.NewDocumentFromNode(m.node).Selection
+	if len(f.fields) > 0 {
+		return extractFields(f.fields, sel, base)
+	}
+
+	attr := f.attr
+	if m.attr != "" {
+		attr = m.attr
+	}
+
+	var text string
+	switch {
+	case attr != "":
+		value, ok := sel.Attr(attr)
+		if !ok {
+			return nil
+		}
+		text = value
+		if attr == "href" || attr == "src" {
+			text = resolveURL(base, value)
+		}
+	case f.html:
+		text, _ = sel.Html()
+	default:
+		text = sel.Text()
+	}
+	return f.transform(text)
+}
+
+// transform applies the field's transforms to text, returning nil when one
+// rejects it
+func (f field) transform(text string) any {
+	for _, transform := range f.transforms {
+		var ok bool
+		if text, ok = transform(text); !ok {
+			return nil
+		}
+	}
+	if f.number {
+		return parseNumber(text)
+	}
+	return text
+}
+
+// parseNumber parses the first number in s, or returns nil when there is none
+func parseNumber(s string) any {
+	match := numberPattern.FindString(s)
+	if match == "" {
+		return nil
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(match, ",", ""), 64)
+	if err != nil {
+		return nil
+	}
+	return n
+}
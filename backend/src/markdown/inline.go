@@ -0,0 +1,229 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// hardBreak is how <br> is written: two trailing spaces and a newline
+const hardBreak = "  \n"
+
+// lineStartPattern matches text at the start of a line that Markdown would
+// read as a heading, list marker, quote or rule
+var lineStartPattern = regexp.MustCompile(`^(#{1,6}(\s|$)|[-+](\s|$)|>|=+\s*$|(-\s*){3,}$)`)
+
+// orderedMarkerPattern matches text at the start of a line that reads as an ordered list item
+var orderedMarkerPattern = regexp.MustCompile(`^(\d+)([.)])(\s|$)`)
+
+// renderInline renders a node in inline context
+func (r *renderer) renderInline(sel *goquery.Selection) string {
+	node := sel.Get(0)
+	switch node.Type {
+	case html.TextNode:
+		return escapeText(collapseSpaces(node.Data))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	tag := node.Data
+	if skippedTags[tag] {
+		return ""
+	}
+
+	switch tag {
+	case "strong", "b":
+		return wrap(r.inlineChildren(sel), "**")
+	case "em", "i":
+		return wrap(r.inlineChildren(sel), "*")
+	case "del", "s", "strike":
+		return wrap(r.inlineChildren(sel), "~~")
+	case "code", "kbd", "samp":
+		return codeSpan(strings.Join(strings.Fields(sel.Text()), " "))
+	case "br":
+		return hardBreak
+	case "img":
+		return r.renderImage(sel)
+	case "a":
+		return r.renderLink(sel)
+	}
+
+	text := r.inlineChildren(sel)
+	if blockTags[tag] {
+		// A block inside inline content still separates words
+		return " " + text + " "
+	}
+	return text
+}
+
+// inlineChildren renders the children of sel in inline context
+func (r *renderer) inlineChildren(sel *goquery.Selection) string {
+	var out strings.Builder
+	sel.Contents().Each(func(i int, child *goquery.Selection) {
+		out.WriteString(r.renderInline(child))
+	})
+	return out.String()
+}
+
+// renderLink renders an anchor. Links without a usable target keep only their text.
+func (r *renderer) renderLink(sel *goquery.Selection) string {
+	text := r.inlineChildren(sel)
+	href := strings.TrimSpace(sel.AttrOr("href", ""))
+	if href == "" || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return text
+	}
+	href = r.resolve(href)
+
+	label := strings.TrimSpace(text)
+	if label == "" {
+		return text
+	}
+	link := "[" + label + "](" + destination(href, sel.AttrOr("title", "")) + ")"
+	return leadingSpace(text) + link + trailingSpace(text)
+}
+
+// renderImage renders an <img> element
+func (r *renderer) renderImage(sel *goquery.Selection) string {
+	src := imageSource(sel)
+	if src == "" {
+		return ""
+	}
+	src = r.resolve(src)
+	alt := escapeText(strings.Join(strings.Fields(sel.AttrOr("alt", "")), " "))
+	return "![" + alt + "](" + destination(src, sel.AttrOr("title", "")) + ")"
+}
+
+// destination formats a link destination with an optional title
+func destination(url, title string) string {
+	url = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(url)
+	title = strings.Join(strings.Fields(title), " ")
+	if title == "" {
+		return url
+	}
+	return url + ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+}
+
+// wrap surrounds inline content with an emphasis marker, keeping
+// surrounding whitespace outside the markers
+func wrap(text, marker string) string {
+	inner := strings.TrimSpace(text)
+	if inner == "" {
+		return text
+	}
+	return leadingSpace(text) + marker + inner + marker + trailingSpace(text)
+}
+
+// codeSpan wraps text in enough backticks that it cannot close early
+func codeSpan(text string) string {
+	if text == "" {
+		return ""
+	}
+	fence := strings.Repeat("`", longestRun(text, '`')+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return fence + text + fence
+}
+
+// finishInline tidies rendered inline content into paragraph lines: it trims
+// the whitespace left around hard breaks and escapes line starts that would
+// otherwise be read as block syntax
+func finishInline(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	out := lines[:0]
+	for i, line := range lines {
+		hard := i < len(lines)-1
+		line = strings.TrimSpace(line)
+		if line == "" {
+			// Consecutive breaks collapse into a paragraph break
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out[len(out)-1] = strings.TrimSuffix(out[len(out)-1], "  ")
+				out = append(out, "")
+			}
+			continue
+		}
+		line = escapeLineStart(line)
+		if hard {
+			line += "  "
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// escapeText escapes Markdown syntax characters in text content.
+// Underscores inside words are left alone, as GFM does not treat them as emphasis.
+func escapeText(text string) string {
+	runes := []rune(text)
+	var out strings.Builder
+	for i, r := range runes {
+		switch r {
+		case '\\', '*', '`', '[', ']':
+			out.WriteRune('\\')
+		case '_':
+			if i == 0 || i == len(runes)-1 || !isWordRune(runes[i-1]) || !isWordRune(runes[i+1]) {
+				out.WriteRune('\\')
+			}
+		case '<':
+			if i < len(runes)-1 && (unicode.IsLetter(runes[i+1]) || strings.ContainsRune("/!?", runes[i+1])) {
+				out.WriteRune('\\')
+			}
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// escapeLineStart escapes a line that would start a heading, list, quote or rule
+func escapeLineStart(line string) string {
+	if lineStartPattern.MatchString(line) {
+		return `\` + line
+	}
+	if m := orderedMarkerPattern.FindStringSubmatchIndex(line); m != nil {
+		return line[:m[3]] + `\` + line[m[3]:]
+	}
+	return line
+}
+
+// collapseSpaces replaces runs of whitespace with a single space
+func collapseSpaces(text string) string {
+	var out strings.Builder
+	space := false
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			if !space {
+				out.WriteRune(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// leadingSpace returns " " if text starts with whitespace
+func leadingSpace(text string) string {
+	if text != strings.TrimLeftFunc(text, unicode.IsSpace) {
+		return " "
+	}
+	return ""
+}
+
+// trailingSpace returns " " if text ends with whitespace
+func trailingSpace(text string) string {
+	if text != strings.TrimRightFunc(text, unicode.IsSpace) {
+		return " "
+	}
+	return ""
+}
+
+// isWordRune reports whether r can be part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
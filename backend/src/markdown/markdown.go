@@ -0,0 +1,292 @@
+// Package markdown converts parsed HTML documents into GitHub-flavored Markdown.
+//
+// The renderer walks the node tree: block elements (headings, paragraphs,
+// lists, quotes, code blocks, tables) become Markdown blocks separated by
+// blank lines, and everything in between is rendered inline with emphasis,
+// links, images, code spans and line breaks preserved.
+package markdown
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// skippedTags hold no readable content
+var skippedTags = map[string]bool{
+	"head":     true,
+	"script":   true,
+	"style":    true,
+	"noscript": true,
+	"template": true,
+	"iframe":   true,
+	"object":   true,
+	"embed":    true,
+	"svg":      true,
+	"canvas":   true,
+	"select":   true,
+	"textarea": true,
+}
+
+// blockTags start a new Markdown block
+var blockTags = map[string]bool{
+	"address":    true,
+	"article":    true,
+	"aside":      true,
+	"blockquote": true,
+	"body":       true,
+	"caption":    true,
+	"center":     true,
+	"dd":         true,
+	"details":    true,
+	"dialog":     true,
+	"div":        true,
+	"dl":         true,
+	"dt":         true,
+	"fieldset":   true,
+	"figcaption": true,
+	"figure":     true,
+	"footer":     true,
+	"form":       true,
+	"h1":         true,
+	"h2":         true,
+	"h3":         true,
+	"h4":         true,
+	"h5":         true,
+	"h6":         true,
+	"header":     true,
+	"hr":         true,
+	"html":       true,
+	"li":         true,
+	"main":       true,
+	"menu":       true,
+	"nav":        true,
+	"ol":         true,
+	"p":          true,
+	"pre":        true,
+	"section":    true,
+	"summary":    true,
+	"table":      true,
+	"tbody":      true,
+	"td":         true,
+	"tfoot":      true,
+	"th":         true,
+	"thead":      true,
+	"tr":         true,
+	"ul":         true,
+	"video":      true,
+}
+
+// block is a rendered Markdown block
+type block struct {
+	text string
+	list bool // Lists nest tightly under the text of a list item
+}
+
+// renderer holds the state of a single conversion
+type renderer struct {
+	base *url.URL // Base for relative link and image URLs, if known
+}
+
+// Convert renders the body of doc as Markdown. Relative link and image URLs
+// are resolved against BaseURL(doc) when doc.Url is set.
+func Convert(doc *goquery.Document) string {
+	body := doc.Find("body").First()
+	if body.Length() == 0 {
+		body = doc.Selection
+	}
+
+	r := &renderer{base: BaseURL(doc)}
+	result := joinBlocks(r.blocks(body), false)
+	if result == "" {
+		// Fallback to body text
+		return strings.TrimSpace(body.Text())
+	}
+	return result + "\n"
+}
+
+// blocks renders the children of sel as a sequence of Markdown blocks.
+// Runs of inline content between block elements become paragraphs.
+func (r *renderer) blocks(sel *goquery.Selection) []block {
+	var out []block
+	var inline strings.Builder
+	flush := func() {
+		if text := finishInline(inline.String()); text != "" {
+			out = append(out, block{text: text})
+		}
+		inline.Reset()
+	}
+
+	sel.Contents().Each(func(i int, child *goquery.Selection) {
+		if isBlock(child) {
+			flush()
+			out = append(out, r.renderBlock(child)...)
+			return
+		}
+		inline.WriteString(r.renderInline(child))
+	})
+	flush()
+
+	return out
+}
+
+// renderBlock renders a single block element
+func (r *renderer) renderBlock(sel *goquery.Selection) []block {
+	tag := goquery.NodeName(sel)
+	switch tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(tag[1] - '0')
+		text := strings.Join(strings.Fields(finishInline(r.inlineChildren(sel))), " ")
+		if text == "" {
+			return nil
+		}
+		return []block{{text: strings.Repeat("#", level) + " " + text}}
+	case "p":
+		if text := finishInline(r.inlineChildren(sel)); text != "" {
+			return []block{{text: text}}
+		}
+		return nil
+	case "ul", "ol":
+		return r.renderList(sel, tag == "ol")
+	case "pre":
+		return []block{{text: renderCodeBlock(sel)}}
+	case "blockquote":
+		text := joinBlocks(r.blocks(sel), false)
+		if text == "" {
+			return nil
+		}
+		return []block{{text: prefixLines(text, "> ", ">")}}
+	case "hr":
+		return []block{{text: "---"}}
+	case "table":
+		return r.renderTable(sel)
+	default:
+		// Containers contribute the blocks inside them
+		return r.blocks(sel)
+	}
+}
+
+// renderList renders a list, indenting nested lists under their items
+func (r *renderer) renderList(sel *goquery.Selection, ordered bool) []block {
+	start := 1
+	if ordered {
+		if n, err := strconv.Atoi(sel.AttrOr("start", "1")); err == nil {
+			start = n
+		}
+	}
+
+	var items []string
+	sel.Children().Each(func(i int, child *goquery.Selection) {
+		if skippedTags[goquery.NodeName(child)] {
+			return
+		}
+		// A list placed directly inside a list belongs to the previous item
+		if child.Is("ul, ol") && len(items) > 0 {
+			if nested := joinBlocks(r.renderBlock(child), true); nested != "" {
+				items[len(items)-1] += "\n" + nested
+			}
+			return
+		}
+		if content := joinBlocks(r.blocks(child), true); content != "" {
+			items = append(items, content)
+		}
+	})
+	if len(items) == 0 {
+		return nil
+	}
+
+	var list strings.Builder
+	for i, content := range items {
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", start+i)
+		}
+		if i > 0 {
+			list.WriteString("\n")
+		}
+		list.WriteString(marker)
+		list.WriteString(prefixLines(content, strings.Repeat(" ", len(marker)), "")[len(marker):])
+	}
+
+	return []block{{text: list.String(), list: true}}
+}
+
+// renderCodeBlock renders a <pre> element as a fenced code block
+func renderCodeBlock(sel *goquery.Selection) string {
+	code := strings.TrimSuffix(sel.Text(), "\n")
+	code = strings.TrimPrefix(code, "\n")
+
+	lang := codeLanguage(sel.Find("code").First())
+	if lang == "" {
+		lang = codeLanguage(sel)
+	}
+
+	fence := strings.Repeat("`", max(3, longestRun(code, '`')+1))
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+// codeLanguage reads a language-* or lang-* class
+func codeLanguage(sel *goquery.Selection) string {
+	for _, class := range strings.Fields(sel.AttrOr("class", "")) {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if lang, ok := strings.CutPrefix(class, prefix); ok {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// joinBlocks separates blocks with blank lines. Inside list items (tight),
+// nested lists follow the item text directly.
+func joinBlocks(blocks []block, tight bool) string {
+	var out strings.Builder
+	for i, b := range blocks {
+		if i > 0 {
+			if tight && b.list {
+				out.WriteString("\n")
+			} else {
+				out.WriteString("\n\n")
+			}
+		}
+		out.WriteString(b.text)
+	}
+	return out.String()
+}
+
+// prefixLines prefixes every line of text, using emptyPrefix for blank lines
+func prefixLines(text, prefix, emptyPrefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = emptyPrefix
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isBlock reports whether sel is an element that starts a new block
+func isBlock(sel *goquery.Selection) bool {
+	node := sel.Get(0)
+	return node.Type == html.ElementNode && blockTags[node.Data]
+}
+
+// longestRun returns the length of the longest run of c in s
+func longestRun(s string, c byte) int {
+	longest, run := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
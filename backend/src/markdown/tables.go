@@ -0,0 +1,257 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// maxSpan caps colspan and rowspan attributes so a malformed value cannot blow up a table
+const maxSpan = 100
+
+// tableRow is a row of cell contents and whether every cell was a <th>
+type tableRow struct {
+	cells  []string
+	header bool
+}
+
+// Tables returns every data table in the document as plain text cells.
+// Layout tables (tables that contain other tables) are skipped in favour
+// of the tables inside them, and so are tables without any text.
+func Tables(doc *goquery.Document) []models.Table {
+	tables := []models.Table{}
+	doc.Find("table").Each(func(i int, sel *goquery.Selection) {
+		if isLayoutTable(sel) {
+			return
+		}
+		if table := parseTable(sel, plainCell); !isEmptyTable(table) {
+			tables = append(tables, table)
+		}
+	})
+	return tables
+}
+
+// renderTable renders a table as a GitHub-flavored Markdown table. Layout
+// tables cannot be expressed in GFM, so their cells are rendered as blocks.
+func (r *renderer) renderTable(table *goquery.Selection) []block {
+	if isLayoutTable(table) {
+		var out []block
+		ownRows(table).Each(func(i int, tr *goquery.Selection) {
+			tr.ChildrenFiltered("th, td").Each(func(j int, cell *goquery.Selection) {
+				out = append(out, r.blocks(cell)...)
+			})
+		})
+		return out
+	}
+
+	data := parseTable(table, r.markdownCell)
+	if isEmptyTable(data) {
+		return nil
+	}
+	headers := data.Headers
+	if headers == nil {
+		// GFM requires a header row, so tables without one get a blank header
+		headers = make([]string, len(data.Rows[0]))
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+
+	var out []block
+	if caption := finishInline(r.inlineChildren(table.ChildrenFiltered("caption").First())); caption != "" {
+		out = append(out, block{text: caption})
+	}
+
+	var markdown strings.Builder
+	writeTableRow(&markdown, headers)
+	separator := make([]string, len(headers))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeTableRow(&markdown, separator)
+	for _, row := range data.Rows {
+		writeTableRow(&markdown, row)
+	}
+
+	return append(out, block{text: strings.TrimSuffix(markdown.String(), "\n")})
+}
+
+// isLayoutTable reports whether a table nests other tables
+func isLayoutTable(table *goquery.Selection) bool {
+	return table.Find("table").Length() > 0
+}
+
+// isEmptyTable reports whether a parsed table has no cell with any text,
+// such as a spacer table or one whose rows are all empty
+func isEmptyTable(table models.Table) bool {
+	for _, cell := range table.Headers {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	for _, row := range table.Rows {
+		for _, cell := range row {
+			if strings.TrimSpace(cell) != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseTable reads a table into a header row and body rows of equal width,
+// using cellText to render each cell
+func parseTable(table *goquery.Selection, cellText func(*goquery.Selection) string) models.Table {
+	result := models.Table{
+		Caption: plainCell(table.ChildrenFiltered("caption").First()),
+		Rows:    [][]string{},
+	}
+
+	var rows []tableRow
+	var headRows int
+	var covered []int // Further rows each column is covered for by a rowspan
+	var group *html.Node
+	ownRows(table).Each(func(i int, tr *goquery.Selection) {
+		// Rowspans end with their <thead>, <tbody> or <tfoot>
+		if parent := tr.Parent().Get(0); parent != group {
+			group, covered = parent, nil
+		}
+		row := parseRow(tr, cellText, &covered)
+		if len(row.cells) == 0 {
+			return
+		}
+		if tr.Parent().Is("thead") {
+			headRows++
+			row.header = true
+		}
+		rows = append(rows, row)
+	})
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row.cells))
+	}
+	for i := range rows {
+		for len(rows[i].cells) < width {
+			rows[i].cells = append(rows[i].cells, "")
+		}
+	}
+
+	// The header is the first <thead> row, or else a leading row made only of <th> cells
+	switch {
+	case headRows > 0:
+		for _, row := range rows {
+			if row.header && result.Headers == nil {
+				result.Headers = row.cells
+				continue
+			}
+			result.Rows = append(result.Rows, row.cells)
+		}
+	case len(rows) > 0 && rows[0].header:
+		result.Headers = rows[0].cells
+		for _, row := range rows[1:] {
+			result.Rows = append(result.Rows, row.cells)
+		}
+	default:
+		for _, row := range rows {
+			result.Rows = append(result.Rows, row.cells)
+		}
+	}
+
+	return result
+}
+
+// ownRows returns the rows of table, excluding rows of nested tables
+func ownRows(table *goquery.Selection) *goquery.Selection {
+	return table.Find("tr").FilterFunction(func(i int, tr *goquery.Selection) bool {
+		return tr.Closest("table").IsSelection(table)
+	})
+}
+
+// parseRow reads the cells of a row, expanding colspans into empty cells and
+// leaving empty the columns covered by rowspans from earlier rows. covered
+// counts the further rows each column is covered for, and is updated for the
+// rows below.
+func parseRow(tr *goquery.Selection, cellText func(*goquery.Selection) string, covered *[]int) tableRow {
+	row := tableRow{header: true}
+	// skipCovered fills in the columns a rowspan covers, up to column end
+	skipCovered := func(end int) {
+		for col := len(row.cells); col < min(end, len(*covered)); col++ {
+			if (*covered)[col] == 0 {
+				return
+			}
+			(*covered)[col]--
+			row.cells = append(row.cells, "")
+		}
+	}
+
+	cells := tr.ChildrenFiltered("th, td")
+	cells.Each(func(i int, cell *goquery.Selection) {
+		skipCovered(len(*covered))
+		if !cell.Is("th") {
+			row.header = false
+		}
+
+		colspan, rowspan := cellSpan(cell, "colspan"), cellSpan(cell, "rowspan")
+		for j := 0; j < colspan; j++ {
+			text := ""
+			if j == 0 {
+				text = cellText(cell)
+			}
+			col := len(row.cells)
+			row.cells = append(row.cells, text)
+			for len(*covered) <= col {
+				*covered = append(*covered, 0)
+			}
+			(*covered)[col] = rowspan - 1
+		}
+	})
+	if cells.Length() == 0 {
+		row.header = false
+	}
+
+	// Columns past the last cell can still be covered from above
+	for col := len(row.cells); col < len(*covered); col++ {
+		if (*covered)[col] > 0 {
+			for len(row.cells) < col {
+				row.cells = append(row.cells, "")
+			}
+			skipCovered(col + 1)
+		}
+	}
+	return row
+}
+
+// cellSpan reads a colspan or rowspan attribute, treating invalid values as 1
+func cellSpan(cell *goquery.Selection, attr string) int {
+	span, err := strconv.Atoi(cell.AttrOr(attr, "1"))
+	if err != nil || span < 1 {
+		return 1
+	}
+	return min(span, maxSpan)
+}
+
+// plainCell returns the whitespace-collapsed text of a cell
+func plainCell(cell *goquery.Selection) string {
+	return strings.Join(strings.Fields(cell.Text()), " ")
+}
+
+// markdownCell renders a cell's inline content on a single line,
+// turning line breaks into <br>
+func (r *renderer) markdownCell(cell *goquery.Selection) string {
+	text := finishInline(r.inlineChildren(cell))
+	return strings.ReplaceAll(text, hardBreak, "<br>")
+}
+
+// writeTableRow writes one Markdown table row, escaping pipes inside cells
+func writeTableRow(markdown *strings.Builder, cells []string) {
+	markdown.WriteString("|")
+	for _, cell := range cells {
+		cell = strings.ReplaceAll(cell, "\n", " ")
+		markdown.WriteString(" " + strings.ReplaceAll(cell, "|", `\|`) + " |")
+	}
+	markdown.WriteString("\n")
+}
@@ -0,0 +1,103 @@
+package markdown
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// lazySourceAttrs hold the real image URL on lazy-loaded images, in order of preference
+var lazySourceAttrs = []string{"data-src", "data-lazy-src", "data-original", "data-url"}
+
+// lazySrcsetAttrs hold candidate lists for images, in order of preference
+var lazySrcsetAttrs = []string{"data-srcset", "srcset"}
+
+// BaseURL returns the URL that relative references in doc resolve against:
+// doc.Url (the page's final, post-redirect URL) combined with any <base href>.
+// It returns nil when neither is known.
+func BaseURL(doc *goquery.Document) *url.URL {
+	base := doc.Url
+	href, ok := doc.Find("base[href]").First().Attr("href")
+	if !ok {
+		return base
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return base
+	}
+	if base == nil {
+		if ref.IsAbs() {
+			return ref
+		}
+		return nil
+	}
+	return base.ResolveReference(ref)
+}
+
+// resolve makes ref absolute against the renderer's base URL.
+// References that cannot be parsed are returned unchanged.
+func (r *renderer) resolve(ref string) string {
+	if r.base == nil {
+		return ref
+	}
+	u, err := r.base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// imageSource picks the real URL of an image. Lazy loaders park it in a
+// data-* attribute and leave a placeholder (or nothing) in src; images with
+// only a srcset use their largest candidate.
+func imageSource(sel *goquery.Selection) string {
+	for _, attr := range lazySourceAttrs {
+		if src := strings.TrimSpace(sel.AttrOr(attr, "")); src != "" {
+			return src
+		}
+	}
+
+	src := strings.TrimSpace(sel.AttrOr("src", ""))
+	if src != "" && !strings.HasPrefix(src, "data:") {
+		return src
+	}
+
+	for _, attr := range lazySrcsetAttrs {
+		if best := largestCandidate(sel.AttrOr(attr, "")); best != "" {
+			return best
+		}
+	}
+	// A <picture> may carry its sources on <source> elements instead
+	if best := largestCandidate(sel.Parent().Filter("picture").Find("source[srcset]").First().AttrOr("srcset", "")); best != "" {
+		return best
+	}
+
+	return src
+}
+
+// largestCandidate returns the URL with the largest width or density
+// descriptor in a srcset attribute
+func largestCandidate(srcset string) string {
+	var best string
+	var bestSize float64
+	for _, candidate := range strings.Split(srcset, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		size := 1.0
+		if len(fields) > 1 {
+			descriptor := fields[len(fields)-1]
+			if n, err := strconv.ParseFloat(descriptor[:len(descriptor)-1], 64); err == nil {
+				size = n
+			}
+		}
+		if best == "" || size > bestSize {
+			best, bestSize = fields[0], size
+		}
+	}
+	return best
+}
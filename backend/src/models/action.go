@@ -0,0 +1,39 @@
+package models
+
+// ActionType names a scripted browser action
+type ActionType string
+
+const (
+	ActionClick           ActionType = "click"           // Click Selector
+	ActionTypeText        ActionType = "type"            // Type Text into Selector
+	ActionPress           ActionType = "press"           // Press Key, after focusing Selector if given
+	ActionScroll          ActionType = "scroll"          // Scroll down Times viewport heights, or ToBottom
+	ActionWaitForSelector ActionType = "waitForSelector" // Wait until Selector is visible
+	ActionWait            ActionType = "wait"            // Wait Ms milliseconds
+	ActionSelect          ActionType = "select"          // Choose the option with Value in the <select> at Selector
+	ActionEvaluate        ActionType = "evaluate"        // Run Script, awaiting it if it returns a promise
+)
+
+// Valid reports whether t is a known action type
+func (t ActionType) Valid() bool {
+	switch t {
+	case ActionClick, ActionTypeText, ActionPress, ActionScroll, ActionWaitForSelector, ActionWait, ActionSelect, ActionEvaluate:
+		return true
+	}
+	return false
+}
+
+// BrowserAction is a step run in the browser tab after the page loads and
+// before its HTML is captured. Selectors are CSS selectors.
+type BrowserAction struct {
+	Type      ActionType `json:"type"`                // Action to run
+	Selector  string     `json:"selector,omitempty"`  // Target element
+	Text      string     `json:"text,omitempty"`      // Text to type
+	Key       string     `json:"key,omitempty"`       // Key to press: a character or a name such as Enter, Tab, Escape, ArrowDown or PageDown
+	Value     string     `json:"value,omitempty"`     // Option value to select
+	Times     int        `json:"times,omitempty"`     // Viewport heights to scroll (default 1)
+	ToBottom  bool       `json:"toBottom,omitempty"`  // Keep scrolling until the page stops growing
+	Ms        int        `json:"ms,omitempty"`        // Milliseconds to wait
+	Script    string     `json:"script,omitempty"`    // JavaScript to evaluate
+	TimeoutMs int        `json:"timeoutMs,omitempty"` // How long to wait for Selector (default 10000)
+}
@@ -0,0 +1,21 @@
+package models
+
+import "encoding/json"
+
+// APICaptureOptions selects the XHR and fetch responses captured while a
+// page renders in the browser
+type APICaptureOptions struct {
+	Patterns     []string `json:"patterns,omitempty"`     // URL patterns to capture, where * matches any run of characters (default: every URL)
+	ContentTypes []string `json:"contentTypes,omitempty"` // MIME types to capture, with the same wildcards (default: application/json and *+json)
+}
+
+// APIResponse is an XHR or fetch response captured from a rendered page
+type APIResponse struct {
+	URL         string          `json:"url"`                   // URL the page requested
+	Method      string          `json:"method"`                // HTTP method of the request
+	RequestBody string          `json:"requestBody,omitempty"` // Body the page sent, such as a GraphQL query
+	Status      int             `json:"status"`                // HTTP status code
+	ContentType string          `json:"contentType"`           // MIME type of the response
+	Data        json.RawMessage `json:"data,omitempty"`        // Response body, when it is valid JSON
+	Body        string          `json:"body,omitempty"`        // Response body as text, when it is not
+}
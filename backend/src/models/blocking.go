@@ -0,0 +1,33 @@
+package models
+
+// ResourceKind is a class of subresource the browser can be told not to load
+type ResourceKind string
+
+const (
+	ResourceImage      ResourceKind = "image"      // Images, including favicons
+	ResourceMedia      ResourceKind = "media"      // Audio and video
+	ResourceFont       ResourceKind = "font"       // Web fonts
+	ResourceStylesheet ResourceKind = "stylesheet" // CSS
+)
+
+// Valid reports whether k is a known resource kind
+func (k ResourceKind) Valid() bool {
+	switch k {
+	case ResourceImage, ResourceMedia, ResourceFont, ResourceStylesheet:
+		return true
+	}
+	return false
+}
+
+// BlockOptions lists the requests a browser tab fails instead of loading.
+// Document requests are never blocked.
+type BlockOptions struct {
+	Resources []ResourceKind `json:"resources,omitempty"` // Resource kinds to block
+	Patterns  []string       `json:"patterns,omitempty"`  // URL patterns to block, where * matches any run of characters
+	Domains   []string       `json:"domains,omitempty"`   // Hosts to block, including their subdomains
+}
+
+// Empty reports whether the options block nothing
+func (o BlockOptions) Empty() bool {
+	return len(o.Resources) == 0 && len(o.Patterns) == 0 && len(o.Domains) == 0
+}
@@ -0,0 +1,36 @@
+package models
+
+// BrowserLog is what a browser tab reported while a page rendered: console
+// output, uncaught script errors and requests that failed to load
+type BrowserLog struct {
+	Console        []ConsoleMessage `json:"console,omitempty"`        // console.* calls and browser messages, in order
+	Exceptions     []JSException    `json:"exceptions,omitempty"`     // Uncaught exceptions and unhandled promise rejections
+	FailedRequests []FailedRequest  `json:"failedRequests,omitempty"` // Requests that failed or got an HTTP error status
+	Dropped        int              `json:"dropped,omitempty"`        // Entries left out past the per-page cap
+}
+
+// ConsoleMessage is a message written to the browser console
+type ConsoleMessage struct {
+	Level  string `json:"level"`            // log, debug, info, warning or error, or the console method called
+	Text   string `json:"text"`             // Message text, with arguments joined by spaces
+	Source string `json:"source,omitempty"` // Browser subsystem for messages not from console.*, such as security or intervention
+	URL    string `json:"url,omitempty"`    // Script that wrote the message
+	Line   int    `json:"line,omitempty"`   // 1-based line in URL
+}
+
+// JSException is an error a page's script threw and did not catch
+type JSException struct {
+	Message string `json:"message"`         // Error message, such as "TypeError: x is undefined"
+	URL     string `json:"url,omitempty"`   // Script the error was thrown from
+	Line    int    `json:"line,omitempty"`  // 1-based line in URL
+	Stack   string `json:"stack,omitempty"` // Call stack, one frame per line
+}
+
+// FailedRequest is a resource the page could not load
+type FailedRequest struct {
+	URL          string `json:"url"`               // URL requested
+	ResourceType string `json:"resourceType"`      // Browser resource type, such as script, stylesheet or xhr
+	Status       int    `json:"status,omitempty"`  // HTTP status, when the server answered with an error
+	Error        string `json:"error,omitempty"`   // Network error, when no usable response arrived
+	Blocked      bool   `json:"blocked,omitempty"` // Whether the request was blocked rather than failed
+}
@@ -0,0 +1,28 @@
+package models
+
+// ScrapeEventType identifies what happened during a scrape
+type ScrapeEventType string
+
+const (
+	EventFetchStarted  ScrapeEventType = "fetch_started"  // A page fetch is about to start
+	EventFallback      ScrapeEventType = "fallback"       // A fetcher failed or was swapped for another
+	EventPageCompleted ScrapeEventType = "page_completed" // A page was fetched and converted
+	EventLinks         ScrapeEventType = "links"          // Links discovered on a page
+	EventDone          ScrapeEventType = "done"           // The scrape finished successfully
+	EventError         ScrapeEventType = "error"          // The scrape failed
+)
+
+// ScrapeEvent describes progress of a running scrape, as streamed to clients
+type ScrapeEvent struct {
+	Type              ScrapeEventType `json:"type"`                        // Event kind
+	URL               string          `json:"url,omitempty"`               // Page the event refers to
+	Depth             int             `json:"depth,omitempty"`             // Crawl depth of that page
+	Mode              RenderMode      `json:"mode,omitempty"`              // Render mode used for a completed page
+	Title             string          `json:"title,omitempty"`             // Title of a completed page
+	Markdown          string          `json:"markdown,omitempty"`          // Leading part of a completed page's Markdown
+	MarkdownTruncated bool            `json:"markdownTruncated,omitempty"` // Whether Markdown was cut short
+	Links             []Link          `json:"links,omitempty"`             // Links discovered on the page
+	Message           string          `json:"message,omitempty"`           // Fallback or error description
+	Progress          *CrawlProgress  `json:"progress,omitempty"`          // Crawl progress at the time of the event
+	Result            *ScrapeResult   `json:"result,omitempty"`            // Final result on done
+}
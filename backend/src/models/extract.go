@@ -0,0 +1,32 @@
+package models
+
+// ExtractField is one named field of an extraction schema. A field selects
+// elements with a CSS selector or an XPath expression (relative to the
+// enclosing field's match, or the document at the top level) and reads their
+// text, an attribute or their inner HTML. Fields with nested Fields produce an
+// object per match instead.
+type ExtractField struct {
+	Name       string             `json:"name"`                 // Key in the extracted object
+	Selector   string             `json:"selector,omitempty"`   // CSS selector
+	XPath      string             `json:"xpath,omitempty"`      // XPath expression, instead of Selector
+	Attr       string             `json:"attr,omitempty"`       // Attribute to read instead of the text (href and src are made absolute)
+	HTML       bool               `json:"html,omitempty"`       // Read the inner HTML instead of the text
+	List       bool               `json:"list,omitempty"`       // Return every match as an array instead of the first one
+	Fields     []ExtractField     `json:"fields,omitempty"`     // Nested fields, evaluated within each match
+	Transforms []ExtractTransform `json:"transforms,omitempty"` // Applied in order to the value read
+}
+
+// TransformType names a value transform
+type TransformType string
+
+const (
+	TransformTrim   TransformType = "trim"   // Trim surrounding whitespace
+	TransformRegex  TransformType = "regex"  // Keep the first capture group (or whole match) of Pattern; no match yields null
+	TransformNumber TransformType = "number" // Parse the first number in the value, ignoring thousands separators; must come last
+)
+
+// ExtractTransform is a transform applied to an extracted value
+type ExtractTransform struct {
+	Type    TransformType `json:"type"`              // Transform to apply
+	Pattern string        `json:"pattern,omitempty"` // Regular expression for regex transforms
+}
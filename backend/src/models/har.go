@@ -0,0 +1,112 @@
+package models
+
+// HAR is an HTTP Archive 1.2 document recording a page's network traffic.
+// See http://www.softwareishard.com/blog/har-12-spec/
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root of a HAR document
+type HARLog struct {
+	Version string     `json:"version"`           // Always 1.2
+	Creator HARCreator `json:"creator"`           // Tool that recorded the archive
+	Pages   []HARPage  `json:"pages"`             // Pages the entries belong to
+	Entries []HAREntry `json:"entries"`           // One entry per request, in the order they were sent
+	Comment string     `json:"comment,omitempty"` // Notes, such as entries dropped past the recording cap
+}
+
+// HARCreator names the tool that recorded the archive
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HARPage is a page load recorded in the archive
+type HARPage struct {
+	StartedDateTime string         `json:"startedDateTime"` // ISO 8601 time the page started loading
+	ID              string         `json:"id"`              // Referenced by entries' pageref
+	Title           string         `json:"title"`           // Page title
+	PageTimings     HARPageTimings `json:"pageTimings"`     // Milliseconds from the page start, -1 when not reached
+}
+
+// HARPageTimings records when the page's load events fired
+type HARPageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+// HAREntry is a single request and its response
+type HAREntry struct {
+	PageRef         string      `json:"pageref,omitempty"`         // Page the request belongs to
+	StartedDateTime string      `json:"startedDateTime"`           // ISO 8601 time the request started
+	Time            float64     `json:"time"`                      // Total milliseconds, the sum of the non-negative timings
+	Request         HARRequest  `json:"request"`                   // Request as sent
+	Response        HARResponse `json:"response"`                  // Response as received; status 0 when none arrived
+	Cache           struct{}    `json:"cache"`                     // Not recorded
+	Timings         HARTimings  `json:"timings"`                   // Phase durations in milliseconds
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"` // Address the response came from
+	ResourceType    string      `json:"_resourceType,omitempty"`   // Browser resource type, such as document, script or image
+	Error           string      `json:"_error,omitempty"`          // Why the request failed, when it did
+}
+
+// HARRequest is a recorded request
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"` // -1 when unknown
+	BodySize    int            `json:"bodySize"`    // -1 when unknown
+}
+
+// HARResponse is a recorded response
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"` // -1 when unknown
+	BodySize    int            `json:"bodySize"`    // Bytes received over the network, -1 when unknown
+}
+
+// HARNameValue is a header or query string parameter
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARCookie is a cookie sent or set. Cookies are not recorded separately
+// from the Cookie and Set-Cookie headers.
+type HARCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData is a request body
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARContent describes a response body. The body itself is not recorded.
+type HARContent struct {
+	Size     int    `json:"size"` // Decoded bytes
+	MimeType string `json:"mimeType"`
+}
+
+// HARTimings are the phases of a request in milliseconds, -1 when they do not apply
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"` // Includes ssl
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
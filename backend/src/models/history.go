@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// StoredResult is a scrape result persisted in the result store
+type StoredResult struct {
+	ID          string        `json:"id"`                 // Result identifier (matches ScrapeResult.ID)
+	URL         string        `json:"url"`                // Seed URL as requested
+	FetchedAt   time.Time     `json:"fetchedAt"`          // When the scrape ran
+	ContentHash string        `json:"contentHash"`        // SHA-256 of the Markdown of every page
+	Warnings    []string      `json:"warnings,omitempty"` // Warnings raised during the scrape
+	Result      *ScrapeResult `json:"result"`             // The full result
+}
+
+// HistoryEntry summarizes a stored result without its content
+type HistoryEntry struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Title       string    `json:"title"`
+	FetchedAt   time.Time `json:"fetchedAt"`
+	ContentHash string    `json:"contentHash"`
+	PageCount   int       `json:"pageCount"`
+	Warnings    []string  `json:"warnings,omitempty"`
+}
+
+// Summary returns the history entry for a stored result
+func (r *StoredResult) Summary() HistoryEntry {
+	entry := HistoryEntry{
+		ID:          r.ID,
+		URL:         r.URL,
+		FetchedAt:   r.FetchedAt,
+		ContentHash: r.ContentHash,
+		Warnings:    r.Warnings,
+	}
+	if r.Result != nil {
+		entry.Title = r.Result.Title
+		entry.PageCount = len(r.Result.Pages)
+	}
+	return entry
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// JobStatus represents the lifecycle state of an asynchronous scrape job
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// Finished reports whether the job has reached a terminal state
+func (s JobStatus) Finished() bool {
+	return s == JobStatusSucceeded || s == JobStatusFailed || s == JobStatusCancelled
+}
+
+// ScrapeRequest is the JSON body accepted by endpoints that enqueue or run a scrape
+type ScrapeRequest struct {
+	URL string `json:"url"` // Seed URL to scrape
+	ScrapeOptions
+}
+
+// CrawlProgress reports how far a crawl has come
+type CrawlProgress struct {
+	PagesVisited int    `json:"pagesVisited"`         // Pages fetched so far
+	PagesQueued  int    `json:"pagesQueued"`          // URLs waiting in the crawl frontier
+	MaxPages     int    `json:"maxPages"`             // Page cap for this crawl
+	CurrentURL   string `json:"currentUrl,omitempty"` // Page currently being fetched
+}
+
+// Job represents an asynchronous scrape and its eventual result
+type Job struct {
+	ID         string        `json:"id"`                   // Opaque job identifier
+	Request    ScrapeRequest `json:"request"`              // What was asked for
+	Status     JobStatus     `json:"status"`               // Lifecycle state
+	Progress   CrawlProgress `json:"progress"`             // Crawl progress while running
+	Result     *ScrapeResult `json:"result,omitempty"`     // Set once the job succeeds
+	Error      string        `json:"error,omitempty"`      // Set when the job fails or is cancelled
+	CreatedAt  time.Time     `json:"createdAt"`            // When the job was enqueued
+	StartedAt  *time.Time    `json:"startedAt,omitempty"`  // When a worker picked it up
+	FinishedAt *time.Time    `json:"finishedAt,omitempty"` // When it reached a terminal state
+}
@@ -0,0 +1,26 @@
+package models
+
+// Metadata is the structured information a page declares about itself in
+// <head> tags, JSON-LD blocks and microdata/RDFa attributes
+type Metadata struct {
+	Description string            `json:"description,omitempty"` // Meta description, falling back to og:description
+	Canonical   string            `json:"canonical,omitempty"`   // Absolute canonical URL
+	Language    string            `json:"language,omitempty"`    // Document language (<html lang> or Content-Language)
+	Keywords    []string          `json:"keywords,omitempty"`    // Meta keywords
+	Author      string            `json:"author,omitempty"`      // Author from meta tags or JSON-LD
+	Published   string            `json:"published,omitempty"`   // Publication date as written on the page
+	Modified    string            `json:"modified,omitempty"`    // Last modification date as written on the page
+	OpenGraph   map[string]string `json:"openGraph,omitempty"`   // og:* and article:* properties (first value wins)
+	Twitter     map[string]string `json:"twitter,omitempty"`     // twitter:* card tags (first value wins)
+	JSONLD      []map[string]any  `json:"jsonLd,omitempty"`      // JSON-LD objects, with arrays and @graph flattened
+	Microdata   []StructuredItem  `json:"microdata,omitempty"`   // Top-level itemscope items
+	RDFa        []StructuredItem  `json:"rdfa,omitempty"`        // Top-level typeof items
+}
+
+// StructuredItem is a microdata or RDFa item. Property values are strings,
+// or nested StructuredItems for properties that are items themselves.
+type StructuredItem struct {
+	Type       []string         `json:"type,omitempty"`       // itemtype / typeof, expanded with vocab when relative
+	ID         string           `json:"id,omitempty"`         // itemid / resource
+	Properties map[string][]any `json:"properties,omitempty"` // Values by property name, in document order
+}
@@ -0,0 +1,41 @@
+package models
+
+import "strings"
+
+// PaperSize names a standard paper format
+type PaperSize string
+
+// paperDimensions are portrait paper sizes in inches
+var paperDimensions = map[PaperSize][2]float64{
+	"letter":  {8.5, 11},
+	"legal":   {8.5, 14},
+	"tabloid": {11, 17},
+	"ledger":  {17, 11},
+	"a3":      {11.69, 16.54},
+	"a4":      {8.27, 11.69},
+	"a5":      {5.83, 8.27},
+	"a6":      {4.13, 5.83},
+}
+
+// Dimensions returns the paper's portrait width and height in inches.
+// Names are matched case-insensitively.
+func (p PaperSize) Dimensions() (width, height float64, ok bool) {
+	size, ok := paperDimensions[PaperSize(strings.ToLower(string(p)))]
+	return size[0], size[1], ok
+}
+
+// PDFOptions controls printing a page to PDF. Lengths are in inches; unset
+// margins use Chrome's default of 0.4in.
+type PDFOptions struct {
+	Paper          PaperSize `json:"paper,omitempty"`          // Letter (default), Legal, Tabloid, Ledger, A3-A6
+	Width          float64   `json:"width,omitempty"`          // Custom paper width, instead of Paper
+	Height         float64   `json:"height,omitempty"`         // Custom paper height, instead of Paper
+	MarginTop      *float64  `json:"marginTop,omitempty"`      // Top margin
+	MarginBottom   *float64  `json:"marginBottom,omitempty"`   // Bottom margin
+	MarginLeft     *float64  `json:"marginLeft,omitempty"`     // Left margin
+	MarginRight    *float64  `json:"marginRight,omitempty"`    // Right margin
+	Landscape      bool      `json:"landscape,omitempty"`      // Print in landscape orientation
+	Background     bool      `json:"background,omitempty"`     // Print background colors and images
+	HeaderTemplate string    `json:"headerTemplate,omitempty"` // HTML header; supports Chrome's date, title, url, pageNumber and totalPages classes
+	FooterTemplate string    `json:"footerTemplate,omitempty"` // HTML footer, with the same classes as HeaderTemplate
+}
@@ -8,12 +8,120 @@ type Link struct {
 	Text string `json:"text,omitempty"` // Link text or anchor
 }
 
+// Table is a data table found on a page. Cells spanning several columns or
+// rows occupy their first column and row and leave the rest empty.
+type Table struct {
+	Caption string     `json:"caption,omitempty"` // Table caption, if any
+	Headers []string   `json:"headers,omitempty"` // Header row from <thead> or a leading row of <th> cells
+	Rows    [][]string `json:"rows"`              // Body rows, padded to the same width
+}
+
+// RenderMode selects which fetcher renders a page
+type RenderMode string
+
+const (
+	RenderModeStatic  RenderMode = "static"  // Plain HTTP fetch with Colly
+	RenderModeBrowser RenderMode = "browser" // Headless Chrome via Chromedp
+	RenderModeAuto    RenderMode = "auto"    // Static first, escalating to the browser for JS-rendered pages
+)
+
+// Valid reports whether m is a known render mode
+func (m RenderMode) Valid() bool {
+	switch m {
+	case RenderModeStatic, RenderModeBrowser, RenderModeAuto:
+		return true
+	}
+	return false
+}
+
+// CachePolicy controls how a scrape uses the response cache
+type CachePolicy string
+
+const (
+	CachePrefer CachePolicy = "prefer" // Serve fresh entries, revalidate stale ones, fetch on a miss
+	CacheBypass CachePolicy = "bypass" // Always fetch, refreshing the cache
+	CacheOnly   CachePolicy = "only"   // Serve from the cache (even if stale) and never fetch pages
+)
+
+// Valid reports whether p is a known cache policy
+func (p CachePolicy) Valid() bool {
+	switch p {
+	case CachePrefer, CacheBypass, CacheOnly:
+		return true
+	}
+	return false
+}
+
+// ContentMode selects how much of a page is converted to Markdown
+type ContentMode string
+
+const (
+	ContentFull ContentMode = "full" // The whole <body>
+	ContentMain ContentMode = "main" // Only the primary content, without navigation, sidebars or footers
+)
+
+// Valid reports whether m is a known content mode
+func (m ContentMode) Valid() bool {
+	return m == ContentFull || m == ContentMain
+}
+
+// ScrapeOptions controls how a scrape job is performed
+type ScrapeOptions struct {
+	Depth      int                `json:"depth"`                // Maximum crawl depth (1 = seed page only)
+	MaxPages   int                `json:"maxPages"`             // Maximum number of pages to visit
+	Mode       RenderMode         `json:"mode,omitempty"`       // Requested render mode (default from config)
+	Cache      CachePolicy        `json:"cache,omitempty"`      // Response cache policy (default prefer)
+	Content    ContentMode        `json:"content,omitempty"`    // Content to convert to Markdown (default from config)
+	Extract    []ExtractField     `json:"extract,omitempty"`    // Extraction schema applied to every page
+	Screenshot *ScreenshotOptions `json:"screenshot,omitempty"` // Screenshot of the seed page, taken in the browser
+	Actions    []BrowserAction    `json:"actions,omitempty"`    // Steps run on the seed page in the browser before its HTML is captured
+	Wait       *WaitOptions       `json:"wait,omitempty"`       // How pages rendered in the browser are waited on (default from config)
+	Block      *BlockOptions      `json:"block,omitempty"`      // Requests browser tabs fail instead of loading (default from config)
+	HAR        bool               `json:"har,omitempty"`        // Record the seed page's network traffic in the browser as a HAR
+	CaptureAPI *APICaptureOptions `json:"captureApi,omitempty"` // XHR and fetch responses to capture from every page, rendered in the browser
+}
+
+// PageResult represents a single page visited during a crawl
+type PageResult struct {
+	URL          string         `json:"url"`                    // Absolute URL of the page
+	FinalURL     string         `json:"finalUrl,omitempty"`     // URL the page was served from, when redirected
+	ParentURL    string         `json:"parentUrl,omitempty"`    // Page on which this URL was discovered
+	Depth        int            `json:"depth"`                  // Crawl depth (seed page = 1)
+	Title        string         `json:"title"`                  // Page title
+	Markdown     string         `json:"markdown"`               // Main content converted to Markdown
+	Links        []Link         `json:"links"`                  // Discovered links
+	Tables       []Table        `json:"tables"`                 // Data tables on the page
+	Metadata     *Metadata      `json:"metadata,omitempty"`     // Structured metadata declared by the page
+	Extracted    map[string]any `json:"extracted,omitempty"`    // Fields read with the request's extraction schema
+	Blocked      map[string]int `json:"blocked,omitempty"`      // Requests blocked in the browser, by resource type
+	APIResponses []APIResponse  `json:"apiResponses,omitempty"` // XHR and fetch responses captured while the page rendered (the seed page's are on the result)
+	BrowserLog   *BrowserLog    `json:"browserLog,omitempty"`   // Console output, script errors and failed loads, when rendered in the browser (the seed page's is on the result)
+	UserAgent    string         `json:"userAgent"`              // User agent presented when fetching the page
+	Mode         RenderMode     `json:"mode"`                   // Render mode actually used (static or browser)
+	Cached       bool           `json:"cached"`                 // Whether the page was served from the response cache
+	Age          int            `json:"age,omitempty"`          // Seconds since the cached copy was fetched
+}
+
 // ScrapeResult represents the output from a scrape job
 type ScrapeResult struct {
-	Title     string    `json:"title"`              // Page title
-	RawHTML   string    `json:"rawHtml"`            // Raw HTML content of the page
-	Markdown  string    `json:"markdown"`           // Main content converted to Markdown
-	Links     []Link    `json:"links"`              // Discovered links
-	Warnings  []string  `json:"warnings,omitempty"` // Optional warnings (robots.txt, fallback, etc.)
-	FetchedAt time.Time `json:"fetchedAt"`          // ISO-8601 timestamp
+	ID           string         `json:"id,omitempty"`           // Identifier in the result store, if persisted
+	Title        string         `json:"title"`                  // Page title
+	RawHTML      string         `json:"rawHtml"`                // Raw HTML content of the page
+	Markdown     string         `json:"markdown"`               // Main content converted to Markdown
+	Links        []Link         `json:"links"`                  // Discovered links
+	Tables       []Table        `json:"tables"`                 // Data tables on the seed page
+	Metadata     *Metadata      `json:"metadata,omitempty"`     // Structured metadata declared by the seed page
+	Extracted    map[string]any `json:"extracted,omitempty"`    // Fields read from the seed page with the extraction schema
+	Screenshot   *Screenshot    `json:"screenshot,omitempty"`   // Screenshot of the seed page, when requested
+	Blocked      map[string]int `json:"blocked,omitempty"`      // Requests blocked in the browser across all pages, by resource type
+	HAR          *HAR           `json:"har,omitempty"`          // Network traffic of the seed page, when requested
+	APIResponses []APIResponse  `json:"apiResponses,omitempty"` // XHR and fetch responses captured while the seed page rendered
+	BrowserLog   *BrowserLog    `json:"browserLog,omitempty"`   // Console output, script errors and failed loads of the seed page, when rendered in the browser
+	Pages        []PageResult   `json:"pages"`                  // Every page visited, in crawl order (seed first)
+	UserAgent    string         `json:"userAgent"`              // User agent presented when fetching the seed page
+	Mode         RenderMode     `json:"mode"`                   // Render mode actually used for the seed page
+	Cached       bool           `json:"cached"`                 // Whether the seed page was served from the response cache
+	Age          int            `json:"age,omitempty"`          // Seconds since the cached seed page was fetched
+	Warnings     []string       `json:"warnings,omitempty"`     // Optional warnings (robots.txt, fallback, etc.)
+	FetchedAt    time.Time      `json:"fetchedAt"`              // ISO-8601 timestamp
 }
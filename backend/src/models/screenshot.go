@@ -0,0 +1,41 @@
+package models
+
+// ImageFormat is the encoding of a captured screenshot
+type ImageFormat string
+
+const (
+	ImagePNG  ImageFormat = "png"  // Lossless PNG
+	ImageJPEG ImageFormat = "jpeg" // JPEG at the requested quality
+)
+
+// Valid reports whether f is a known image format
+func (f ImageFormat) Valid() bool {
+	return f == ImagePNG || f == ImageJPEG
+}
+
+// ContentType returns the MIME type of the format
+func (f ImageFormat) ContentType() string {
+	if f == ImageJPEG {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// ScreenshotOptions controls a screenshot taken in the headless browser.
+// Without FullPage or Selector the visible viewport is captured.
+type ScreenshotOptions struct {
+	Format   ImageFormat `json:"format,omitempty"`   // png (default) or jpeg
+	Quality  int         `json:"quality,omitempty"`  // JPEG quality from 1 to 100 (default 80)
+	FullPage bool        `json:"fullPage,omitempty"` // Capture the whole scrollable page
+	Selector string      `json:"selector,omitempty"` // Capture only the first element matching this CSS selector
+	Width    int         `json:"width,omitempty"`    // Viewport width in CSS pixels (default 1280)
+	Height   int         `json:"height,omitempty"`   // Viewport height in CSS pixels (default 800)
+}
+
+// Screenshot is a captured image. Data is base64-encoded in JSON.
+type Screenshot struct {
+	Format ImageFormat `json:"format"` // Image encoding
+	Width  int         `json:"width"`  // Captured width in CSS pixels
+	Height int         `json:"height"` // Captured height in CSS pixels
+	Data   []byte      `json:"data"`   // Encoded image
+}
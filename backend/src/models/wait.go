@@ -0,0 +1,32 @@
+package models
+
+// WaitStrategy selects how the browser decides a page has finished loading
+type WaitStrategy string
+
+const (
+	WaitNetworkIdle WaitStrategy = "networkIdle" // No requests in flight for Ms
+	WaitDOMStable   WaitStrategy = "domStable"   // No DOM mutations for Ms
+	WaitSelector    WaitStrategy = "selector"    // Selector becomes visible
+	WaitFunction    WaitStrategy = "function"    // Script returns a truthy value
+	WaitDelay       WaitStrategy = "delay"       // A fixed pause of Ms
+)
+
+// Valid reports whether s is a known wait strategy
+func (s WaitStrategy) Valid() bool {
+	switch s {
+	case WaitNetworkIdle, WaitDOMStable, WaitSelector, WaitFunction, WaitDelay:
+		return true
+	}
+	return false
+}
+
+// WaitOptions controls how long the browser waits after the page loads before
+// actions run and the HTML is captured. A wait that times out is reported as
+// a warning and the page is captured as it stands.
+type WaitOptions struct {
+	Strategy  WaitStrategy `json:"strategy"`            // How to wait
+	Ms        int          `json:"ms,omitempty"`        // Quiet period for networkIdle and domStable (default 500), or the delay (default 2000)
+	Selector  string       `json:"selector,omitempty"`  // CSS selector to wait for
+	Script    string       `json:"script,omitempty"`    // JavaScript expression polled until truthy
+	TimeoutMs int          `json:"timeoutMs,omitempty"` // Longest to wait (default from config)
+}
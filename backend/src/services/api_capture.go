@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// maxAPIResponses caps how many responses one page captures
+	maxAPIResponses = 100
+	// maxAPIResponseBytes caps the size of a single captured body
+	maxAPIResponseBytes = 1 << 20
+)
+
+// defaultAPIContentTypes are captured when the options name no content types
+var defaultAPIContentTypes = []string{"application/json", "*+json"}
+
+// apiCapture keeps the bodies of a tab's XHR and fetch responses that match
+// its URL patterns and content types
+type apiCapture struct {
+	patterns []*regexp.Regexp
+	types    []*regexp.Regexp
+
+	mu        sync.Mutex
+	requests  map[network.RequestID]*network.Request // XHR and fetch requests awaiting a response
+	matched   map[network.RequestID]*models.APIResponse
+	responses []models.APIResponse
+	reads     int  // Bodies read or being read, counted against maxAPIResponses
+	skipped   int  // Matching responses dropped for the count or size cap, or whose body could not be read
+	closed    bool // Collected; responses finishing later are ignored
+	fetching  sync.WaitGroup
+}
+
+// newAPICapture compiles capture options for a single tab. It returns nil when
+// opts is nil, so nothing is captured.
+func newAPICapture(opts *models.APICaptureOptions) *apiCapture {
+	if opts == nil {
+		return nil
+	}
+	c := &apiCapture{
+		requests: make(map[network.RequestID]*network.Request),
+		matched:  make(map[network.RequestID]*models.APIResponse),
+	}
+	for _, pattern := range opts.Patterns {
+		c.patterns = append(c.patterns, compileURLPattern(pattern))
+	}
+	types := opts.ContentTypes
+	if len(types) == 0 {
+		types = defaultAPIContentTypes
+	}
+	for _, contentType := range types {
+		c.types = append(c.types, compileURLPattern(contentType))
+	}
+	return c
+}
+
+// listen attaches the capture to the tab in ctx. Call it before the first Run.
+func (c *apiCapture) listen(ctx context.Context) {
+	chromedp.ListenTarget(ctx, func(ev any) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		switch ev := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			if isAPIRequest(ev.Type) {
+				c.requests[ev.RequestID] = ev.Request
+			}
+		case *network.EventResponseReceived:
+			req := c.requests[ev.RequestID]
+			delete(c.requests, ev.RequestID)
+			if req == nil || !isAPIRequest(ev.Type) || !c.matches(ev.Response.URL, ev.Response.MimeType) {
+				return
+			}
+			c.matched[ev.RequestID] = &models.APIResponse{
+				URL:         ev.Response.URL,
+				Method:      req.Method,
+				RequestBody: requestBody(req),
+				Status:      int(ev.Response.Status),
+				ContentType: ev.Response.MimeType,
+			}
+		case *network.EventLoadingFinished:
+			resp := c.matched[ev.RequestID]
+			if resp == nil || c.closed {
+				return
+			}
+			delete(c.matched, ev.RequestID)
+			if c.reads >= maxAPIResponses || ev.EncodedDataLength > maxAPIResponseBytes {
+				c.skipped++
+				return
+			}
+			// Event handlers must not block, so read the body from a goroutine
+			c.reads++
+			c.fetching.Add(1)
+			go c.readBody(ctx, ev.RequestID, resp)
+		case *network.EventLoadingFailed:
+			delete(c.requests, ev.RequestID)
+			delete(c.matched, ev.RequestID)
+		}
+	})
+}
+
+// matches reports whether a response from rawURL with the given MIME type is captured
+func (c *apiCapture) matches(rawURL, mimeType string) bool {
+	if len(c.patterns) > 0 && !matchesAny(c.patterns, rawURL) {
+		return false
+	}
+	return matchesAny(c.types, mimeType)
+}
+
+// readBody fetches a finished response's body from the browser and keeps it
+func (c *apiCapture) readBody(ctx context.Context, id network.RequestID, resp *models.APIResponse) {
+	defer c.fetching.Done()
+	execCtx := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+	body, err := network.GetResponseBody(id).Do(execCtx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil || len(body) > maxAPIResponseBytes {
+		c.skipped++
+		return
+	}
+	if json.Valid(body) {
+		resp.Data = json.RawMessage(body)
+	} else {
+		resp.Body = string(body)
+	}
+	c.responses = append(c.responses, *resp)
+}
+
+// collect waits for bodies still being read and returns the captured
+// responses in the order they finished loading. Responses still loading are
+// left out. The error reports responses that matched but could not be kept.
+func (c *apiCapture) collect(ctx context.Context) ([]models.APIResponse, error) {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.fetching.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	responses := append([]models.APIResponse(nil), c.responses...)
+	if c.skipped > 0 {
+		return responses, fmt.Errorf("%d matching responses were skipped: unreadable, over %d bytes or past the first %d",
+			c.skipped, maxAPIResponseBytes, maxAPIResponses)
+	}
+	return responses, nil
+}
+
+// isAPIRequest reports whether a resource type is a script-issued request
+func isAPIRequest(resourceType network.ResourceType) bool {
+	return resourceType == network.ResourceTypeXHR || resourceType == network.ResourceTypeFetch
+}
+
+// requestBody returns the text body of a request, if it sent one
+func requestBody(req *network.Request) string {
+	var body strings.Builder
+	for _, entry := range req.PostDataEntries {
+		if data, err := base64.StdEncoding.DecodeString(entry.Bytes); err == nil {
+			body.Write(data)
+		}
+	}
+	return body.String()
+}
+
+// matchesAny reports whether any of patterns matches s
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
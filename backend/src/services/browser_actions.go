@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
+)
+
+const (
+	// defaultActionTimeout bounds how long an action waits for its element
+	defaultActionTimeout = 10 * time.Second
+	// scrollPause gives lazy content time to load between scroll steps
+	scrollPause = 500 * time.Millisecond
+	// maxScrollRounds caps scrolling to the bottom of endlessly growing pages
+	maxScrollRounds = 50
+	// browserCaptureTimeout bounds reading the HTML once waits and actions are done
+	browserCaptureTimeout = 5 * time.Second
+)
+
+// namedKeys maps key names accepted by press actions to chromedp key codes
+var namedKeys = map[string]string{
+	"enter":      kb.Enter,
+	"tab":        kb.Tab,
+	"escape":     kb.Escape,
+	"backspace":  kb.Backspace,
+	"delete":     kb.Delete,
+	"space":      " ",
+	"arrowup":    kb.ArrowUp,
+	"arrowdown":  kb.ArrowDown,
+	"arrowleft":  kb.ArrowLeft,
+	"arrowright": kb.ArrowRight,
+	"pageup":     kb.PageUp,
+	"pagedown":   kb.PageDown,
+	"home":       kb.Home,
+	"end":        kb.End,
+}
+
+// selectOptionJS picks the option with a value in a <select> and fires the
+// events a user's choice would. Arguments are JSON strings.
+const selectOptionJS = `(() => {
+	const el = document.querySelector(%s);
+	if (!el) return "no element matches the selector";
+	const option = Array.from(el.options || []).find(o => o.value === %s);
+	if (!option) return "no option has that value";
+	el.value = option.value;
+	el.dispatchEvent(new Event("input", {bubbles: true}));
+	el.dispatchEvent(new Event("change", {bubbles: true}));
+	return "";
+})()`
+
+// scrollHeightJS reads the height of the scrollable document
+const scrollHeightJS = `(document.scrollingElement || document.body).scrollHeight`
+
+// KeyCode returns the chromedp key sequence for a press action's key: a
+// name from namedKeys (case-insensitive) or a single character
+func KeyCode(key string) (string, bool) {
+	if code, ok := namedKeys[strings.ToLower(key)]; ok {
+		return code, true
+	}
+	if len([]rune(key)) == 1 {
+		return key, true
+	}
+	return "", false
+}
+
+// runBrowserActions runs actions in order in the tab behind ctx. It stops at
+// the first failure, returning an error that names the failed action.
+func runBrowserActions(ctx context.Context, actions []models.BrowserAction) error {
+	for i, action := range actions {
+		if err := runBrowserAction(ctx, action); err != nil {
+			return fmt.Errorf("action %d (%s) failed, skipping the rest: %w", i+1, action.Type, err)
+		}
+	}
+	return nil
+}
+
+// actionsBudget is the most time actions can take: each action's timeout, or
+// its duration for waits
+func actionsBudget(actions []models.BrowserAction) time.Duration {
+	var budget time.Duration
+	for _, action := range actions {
+		if action.Type == models.ActionWait {
+			budget += time.Duration(action.Ms) * time.Millisecond
+			continue
+		}
+		budget += actionTimeout(action)
+	}
+	return budget
+}
+
+// actionTimeout returns how long an action may run. A scroll of several
+// viewport heights also gets the pause after each step.
+func actionTimeout(action models.BrowserAction) time.Duration {
+	if action.TimeoutMs > 0 {
+		return time.Duration(action.TimeoutMs) * time.Millisecond
+	}
+	if action.Type == models.ActionScroll && !action.ToBottom {
+		return defaultActionTimeout + time.Duration(max(action.Times, 1))*scrollPause
+	}
+	return defaultActionTimeout
+}
+
+// runBrowserAction runs a single action, bounded by its timeout. Waits last
+// as long as they ask instead.
+func runBrowserAction(ctx context.Context, action models.BrowserAction) error {
+	waitCtx, cancel := context.WithTimeout(ctx, actionTimeout(action))
+	defer cancel()
+
+	switch action.Type {
+	case models.ActionClick:
+		return chromedp.Run(waitCtx, chromedp.Click(action.Selector, chromedp.ByQuery, chromedp.NodeVisible))
+
+	case models.ActionTypeText:
+		return chromedp.Run(waitCtx, chromedp.SendKeys(action.Selector, action.Text, chromedp.ByQuery, chromedp.NodeVisible))
+
+	case models.ActionPress:
+		code, ok := KeyCode(action.Key)
+		if !ok {
+			return fmt.Errorf("unknown key %q", action.Key)
+		}
+		if action.Selector != "" {
+			if err := chromedp.Run(waitCtx, chromedp.Focus(action.Selector, chromedp.ByQuery, chromedp.NodeVisible)); err != nil {
+				return err
+			}
+		}
+		return chromedp.Run(waitCtx, chromedp.KeyEvent(code))
+
+	case models.ActionScroll:
+		return scrollPage(waitCtx, action)
+
+	case models.ActionWaitForSelector:
+		return chromedp.Run(waitCtx, chromedp.WaitVisible(action.Selector, chromedp.ByQuery))
+
+	case models.ActionWait:
+		return sleepContext(ctx, time.Duration(action.Ms)*time.Millisecond)
+
+	case models.ActionSelect:
+		selector, _ := json.Marshal(action.Selector)
+		value, _ := json.Marshal(action.Value)
+		var problem string
+		err := chromedp.Run(waitCtx,
+			chromedp.WaitReady(action.Selector, chromedp.ByQuery),
+			chromedp.Evaluate(fmt.Sprintf(selectOptionJS, selector, value), &problem),
+		)
+		if err == nil && problem != "" {
+			err = fmt.Errorf("%s", problem)
+		}
+		return err
+
+	case models.ActionEvaluate:
+		return chromedp.Run(waitCtx, chromedp.Evaluate(action.Script, nil, awaitPromise))
+	}
+	return fmt.Errorf("unknown action type %q", action.Type)
+}
+
+// scrollPage scrolls down a number of viewport heights, or until the page
+// stops growing when the action asks for the bottom. Scrolling to the bottom
+// of a page that keeps growing stops quietly once ctx's deadline passes.
+func scrollPage(ctx context.Context, action models.BrowserAction) error {
+	if !action.ToBottom {
+		times := max(action.Times, 1)
+		for i := 0; i < times; i++ {
+			if err := chromedp.Run(ctx, chromedp.Evaluate(`window.scrollBy(0, window.innerHeight)`, nil)); err != nil {
+				return err
+			}
+			if err := sleepContext(ctx, scrollPause); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var height float64
+	if err := chromedp.Run(ctx, chromedp.Evaluate(scrollHeightJS, &height)); err != nil {
+		return err
+	}
+	for round := 0; round < maxScrollRounds; round++ {
+		var next float64
+		err := chromedp.Run(ctx,
+			chromedp.Evaluate(`window.scrollTo(0, `+scrollHeightJS+`)`, nil),
+			chromedp.Sleep(scrollPause),
+			chromedp.Evaluate(scrollHeightJS, &next),
+		)
+		if errors.Is(err, context.DeadlineExceeded) && round > 0 {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if next <= height {
+			return nil
+		}
+		height = next
+	}
+	return nil
+}
+
+// awaitPromise makes Evaluate wait for a returned promise to settle
+func awaitPromise(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+	return p.WithAwaitPromise(true)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// browserInterceptor pauses every request a Chromedp tab makes and decides
+// whether it may proceed, so sub-navigations, redirects and page scripts
+// cannot reach addresses the URL guard forbids. It also fails the requests
+// the blocker rules out, counting them by resource type.
+type browserInterceptor struct {
+	guard   *URLGuard
+	blocker *resourceBlocker
+
+	mu          sync.Mutex
+	documentErr error
+	blocked     map[string]int
+}
+
+// newBrowserInterceptor creates an interceptor for a single tab. blocker may be nil.
+func newBrowserInterceptor(guard *URLGuard, blocker *resourceBlocker) *browserInterceptor {
+	return &browserInterceptor{guard: guard, blocker: blocker}
+}
+
+// listen attaches the interceptor to the tab in ctx. Call it before the first Run.
+func (i *browserInterceptor) listen(ctx context.Context) {
+	chromedp.ListenTarget(ctx, func(ev any) {
+		if ev, ok := ev.(*fetch.EventRequestPaused); ok {
+			// Event handlers must not block, so respond from a goroutine
+			go i.handle(ctx, ev)
+		}
+	})
+}
+
+// enable returns the action that turns on request interception for the tab
+func (i *browserInterceptor) enable() chromedp.Action {
+	return fetch.Enable()
+}
+
+// blockedDocument returns the guard error for a blocked document request, if any
+func (i *browserInterceptor) blockedDocument() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.documentErr
+}
+
+// blockedCounts returns how many requests the blocker failed, by resource type,
+// or nil when it failed none
+func (i *browserInterceptor) blockedCounts() map[string]int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if len(i.blocked) == 0 {
+		return nil
+	}
+	return maps.Clone(i.blocked)
+}
+
+// handle continues or fails a single paused request
+func (i *browserInterceptor) handle(ctx context.Context, ev *fetch.EventRequestPaused) {
+	execCtx := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+
+	if err := i.check(ctx, ev.Request.URL); err != nil {
+		if ev.ResourceType == network.ResourceTypeDocument {
+			i.mu.Lock()
+			if i.documentErr == nil {
+				i.documentErr = err
+			}
+			i.mu.Unlock()
+		}
+		_ = fetch.FailRequest(ev.RequestID, network.ErrorReasonBlockedByClient).Do(execCtx)
+		return
+	}
+
+	if i.blocker.blocks(ev.ResourceType, ev.Request.URL) {
+		i.mu.Lock()
+		if i.blocked == nil {
+			i.blocked = make(map[string]int)
+		}
+		i.blocked[strings.ToLower(string(ev.ResourceType))]++
+		i.mu.Unlock()
+		_ = fetch.FailRequest(ev.RequestID, network.ErrorReasonBlockedByClient).Do(execCtx)
+		return
+	}
+
+	_ = fetch.ContinueRequest(ev.RequestID).Do(execCtx)
+}
+
+// check returns a *BlockedTargetError if the guard forbids rawURL.
+// Non-network schemes and lookup failures are left for the browser to handle.
+// WebSocket handshakes never pause here; BrowserProxy guards those.
+func (i *browserInterceptor) check(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+
+	if err := i.guard.CheckURL(ctx, rawURL); isBlockedTarget(err) {
+		return err
+	}
+	return nil
+}
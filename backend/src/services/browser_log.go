@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	cdplog "github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// maxBrowserLogEntries caps the messages, exceptions and failed requests kept per page
+	maxBrowserLogEntries = 500
+	// maxBrowserLogText caps the length of a single message or stack
+	maxBrowserLogText = 4000
+)
+
+// BrowserRenderError is returned when a page could not be rendered in the
+// browser. It keeps what the tab reported before the failure.
+type BrowserRenderError struct {
+	Err error
+	Log *models.BrowserLog
+}
+
+func (e *BrowserRenderError) Error() string {
+	if summary := browserLogSummary(e.Log); summary != "" {
+		return fmt.Sprintf("%v (browser log: %s)", e.Err, summary)
+	}
+	return e.Err.Error()
+}
+
+func (e *BrowserRenderError) Unwrap() error {
+	return e.Err
+}
+
+// failedRenderLog returns the browser log of a failed render, if err carries one
+func failedRenderLog(err error) *models.BrowserLog {
+	var renderErr *BrowserRenderError
+	if errors.As(err, &renderErr) {
+		return renderErr.Log
+	}
+	return nil
+}
+
+// browserLogger collects what a tab reports while a page renders
+type browserLogger struct {
+	mu       sync.Mutex
+	log      models.BrowserLog
+	entries  int
+	requests map[network.RequestID]*network.EventRequestWillBeSent // Requests still loading
+}
+
+// newBrowserLogger creates a logger for a single tab
+func newBrowserLogger() *browserLogger {
+	return &browserLogger{requests: make(map[network.RequestID]*network.EventRequestWillBeSent)}
+}
+
+// listen attaches the logger to the tab in ctx. Call it before the first Run.
+func (l *browserLogger) listen(ctx context.Context) {
+	chromedp.ListenTarget(ctx, func(ev any) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		switch ev := ev.(type) {
+		case *runtime.EventConsoleAPICalled:
+			msg := models.ConsoleMessage{Level: consoleLevel(ev.Type), Text: remoteObjectsText(ev.Args)}
+			if frame := topFrame(ev.StackTrace); frame != nil {
+				msg.URL, msg.Line = frame.URL, int(frame.LineNumber)+1
+			}
+			l.add(func() { l.log.Console = append(l.log.Console, msg) })
+		case *cdplog.EventEntryAdded:
+			// Network messages repeat the failed requests recorded below
+			if ev.Entry.Source == cdplog.SourceNetwork {
+				return
+			}
+			msg := models.ConsoleMessage{
+				Level:  string(ev.Entry.Level),
+				Text:   truncateLogText(ev.Entry.Text),
+				Source: string(ev.Entry.Source),
+				URL:    ev.Entry.URL,
+			}
+			if ev.Entry.LineNumber > 0 {
+				msg.Line = int(ev.Entry.LineNumber) + 1
+			}
+			l.add(func() { l.log.Console = append(l.log.Console, msg) })
+		case *runtime.EventExceptionThrown:
+			exception := jsException(ev.ExceptionDetails)
+			l.add(func() { l.log.Exceptions = append(l.log.Exceptions, exception) })
+		case *network.EventRequestWillBeSent:
+			l.requests[ev.RequestID] = ev
+		case *network.EventResponseReceived:
+			if ev.Response.Status >= 400 {
+				failed := models.FailedRequest{
+					URL:          ev.Response.URL,
+					ResourceType: strings.ToLower(string(ev.Type)),
+					Status:       int(ev.Response.Status),
+				}
+				l.add(func() { l.log.FailedRequests = append(l.log.FailedRequests, failed) })
+			}
+		case *network.EventLoadingFinished:
+			delete(l.requests, ev.RequestID)
+		case *network.EventLoadingFailed:
+			req := l.requests[ev.RequestID]
+			delete(l.requests, ev.RequestID)
+			// Cancelled requests were abandoned by the page, not failed
+			if req == nil || ev.Canceled {
+				return
+			}
+			failed := models.FailedRequest{
+				URL:          req.Request.URL,
+				ResourceType: strings.ToLower(string(ev.Type)),
+				Error:        ev.ErrorText,
+				Blocked:      ev.BlockedReason != "" || ev.ErrorText == "net::ERR_BLOCKED_BY_CLIENT",
+			}
+			if ev.BlockedReason != "" {
+				failed.Error += " (" + string(ev.BlockedReason) + ")"
+			}
+			l.add(func() { l.log.FailedRequests = append(l.log.FailedRequests, failed) })
+		}
+	})
+}
+
+// add records an entry unless the cap is reached. Call with l.mu held.
+func (l *browserLogger) add(record func()) {
+	if l.entries >= maxBrowserLogEntries {
+		l.log.Dropped++
+		return
+	}
+	l.entries++
+	record()
+}
+
+// build returns the log collected so far, or nil when the tab reported nothing
+func (l *browserLogger) build() *models.BrowserLog {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.entries == 0 && l.log.Dropped == 0 {
+		return nil
+	}
+	log := l.log
+	log.Console = append([]models.ConsoleMessage(nil), l.log.Console...)
+	log.Exceptions = append([]models.JSException(nil), l.log.Exceptions...)
+	log.FailedRequests = append([]models.FailedRequest(nil), l.log.FailedRequests...)
+	return &log
+}
+
+// browserLogSummary counts the errors in a browser log for a warning, such as
+// "2 uncaught exceptions, 1 failed request". It is empty when there are none.
+func browserLogSummary(log *models.BrowserLog) string {
+	if log == nil {
+		return ""
+	}
+	var consoleErrors int
+	for _, msg := range log.Console {
+		if msg.Level == "error" {
+			consoleErrors++
+		}
+	}
+	var blocked int
+	for _, req := range log.FailedRequests {
+		if req.Blocked {
+			blocked++
+		}
+	}
+
+	var parts []string
+	for _, count := range []struct {
+		n    int
+		noun string
+	}{
+		{len(log.Exceptions), "uncaught exception"},
+		{consoleErrors, "console error"},
+		{len(log.FailedRequests) - blocked, "failed request"},
+		{blocked, "blocked request"},
+	} {
+		switch {
+		case count.n == 1:
+			parts = append(parts, "1 "+count.noun)
+		case count.n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", count.n, count.noun))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// jsException converts CDP exception details
+func jsException(details *runtime.ExceptionDetails) models.JSException {
+	exception := models.JSException{
+		Message: details.Text,
+		URL:     details.URL,
+		Line:    int(details.LineNumber) + 1,
+	}
+	// The description starts with the error's name and message, followed by V8's stack
+	if details.Exception != nil {
+		if description, _, _ := strings.Cut(details.Exception.Description, "\n"); description != "" {
+			exception.Message = description
+		} else if value := remoteObjectText(details.Exception); value != "" {
+			exception.Message = details.Text + " " + value
+		}
+	}
+	if details.StackTrace != nil {
+		var stack strings.Builder
+		for _, frame := range details.StackTrace.CallFrames {
+			name := frame.FunctionName
+			if name == "" {
+				name = "(anonymous)"
+			}
+			fmt.Fprintf(&stack, "at %s (%s:%d:%d)\n", name, frame.URL, frame.LineNumber+1, frame.ColumnNumber+1)
+		}
+		exception.Stack = truncateLogText(strings.TrimSuffix(stack.String(), "\n"))
+		if exception.URL == "" {
+			if frame := topFrame(details.StackTrace); frame != nil {
+				exception.URL, exception.Line = frame.URL, int(frame.LineNumber)+1
+			}
+		}
+	}
+	exception.Message = truncateLogText(exception.Message)
+	return exception
+}
+
+// consoleLevel maps a console method to a log level; other methods keep their name
+func consoleLevel(apiType runtime.APIType) string {
+	switch apiType {
+	case runtime.APITypeWarning:
+		return "warning"
+	case runtime.APITypeError, runtime.APITypeAssert:
+		return "error"
+	}
+	return string(apiType)
+}
+
+// remoteObjectsText joins console arguments the way the console prints them
+func remoteObjectsText(args []*runtime.RemoteObject) string {
+	texts := make([]string, 0, len(args))
+	for _, arg := range args {
+		texts = append(texts, remoteObjectText(arg))
+	}
+	return truncateLogText(strings.Join(texts, " "))
+}
+
+// remoteObjectText renders a single JavaScript value
+func remoteObjectText(obj *runtime.RemoteObject) string {
+	switch {
+	case obj == nil:
+		return ""
+	case obj.Type == runtime.TypeString:
+		var s string
+		if json.Unmarshal(obj.Value, &s) == nil {
+			return s
+		}
+	case len(obj.Value) > 0:
+		return string(obj.Value)
+	case obj.UnserializableValue != "":
+		return string(obj.UnserializableValue)
+	case obj.Description != "":
+		return obj.Description
+	}
+	return string(obj.Type)
+}
+
+// topFrame returns the innermost frame of a stack trace, if any
+func topFrame(trace *runtime.StackTrace) *runtime.CallFrame {
+	if trace == nil || len(trace.CallFrames) == 0 {
+		return nil
+	}
+	return trace.CallFrames[0]
+}
+
+// truncateLogText caps s at maxBrowserLogText bytes without splitting a character
+func truncateLogText(s string) string {
+	if len(s) <= maxBrowserLogText {
+		return s
+	}
+	cut := maxBrowserLogText
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
@@ -0,0 +1,436 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// healthCheckTimeout bounds how long a healthy browser takes to answer
+	healthCheckTimeout = 5 * time.Second
+	// browserStartTimeout bounds launching a browser or dialing a remote one
+	browserStartTimeout = 30 * time.Second
+	// reconnectMinDelay and reconnectMaxDelay bound the backoff between
+	// attempts to bring a lost browser back
+	reconnectMinDelay = time.Second
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// Browser connection states reported by BrowserPoolStats
+const (
+	BrowserIdle         = "idle"         // Not started yet; starts on first use
+	BrowserStarting     = "starting"     // Being launched or dialed for the first time
+	BrowserConnected    = "connected"    // Up and accepting tabs
+	BrowserReconnecting = "reconnecting" // Being replaced after a recycle, crash or failed health check
+	BrowserDisconnected = "disconnected" // Lost or failed to start; waiting to retry
+)
+
+// ErrBrowserBusy is returned when no browser tab frees up within the queue timeout
+var ErrBrowserBusy = errors.New("all browser tabs are busy")
+
+// ErrBrowserClosed is returned when a tab is requested after the pool is closed
+var ErrBrowserClosed = errors.New("browser pool is closed")
+
+// BrowserPoolStats describes the pool for monitoring
+type BrowserPoolStats struct {
+	Mode      string `json:"mode"`                // local (launched by the service) or remote (CHROME_WS_URL)
+	State     string `json:"state"`               // idle, starting, connected, reconnecting or disconnected
+	LastError string `json:"lastError,omitempty"` // Why the browser last failed to start or connect
+	Running   bool   `json:"running"`             // Whether a browser is up
+	MaxTabs   int    `json:"maxTabs"`             // Tabs that may be open at once
+	OpenTabs  int    `json:"openTabs"`            // Tabs open now
+	Pages     int    `json:"pages"`               // Tabs opened in the current browser
+	Restarts  int    `json:"restarts"`            // Browsers replaced after a crash, disconnect or failed health check
+	Recycled  int    `json:"recycled"`            // Browsers replaced after serving their page quota
+}
+
+// BrowserPool shares one headless browser between scrapes. It caps how many
+// tabs are open at once, queueing the rest, replaces the browser when it
+// crashes, disconnects or stops answering health checks, and can recycle it
+// after a number of pages to cap memory growth.
+type BrowserPool struct {
+	newAllocator func() (context.Context, context.CancelFunc)
+	remote       bool
+	slots        chan struct{}
+	queueTimeout time.Duration
+	recycleAfter int
+
+	mu           sync.Mutex
+	current      *browserInstance
+	launching    *browserLaunch // Launch in progress, shared by everyone waiting for a browser
+	started      bool           // A browser has been up at some point
+	lastErr      error          // Last start or connection failure, cleared on success
+	reconnecting bool
+	restarts     int
+	recycled     int
+	closed       bool
+
+	ctx  context.Context // Lives until Close, for health checks and reconnects
+	stop context.CancelFunc
+}
+
+// browserLaunch is a browser start in progress. err is set before done closes.
+type browserLaunch struct {
+	done chan struct{}
+	err  error
+}
+
+// browserInstance is one running browser process and its open tabs
+type browserInstance struct {
+	ctx     context.Context // Context of the browser's first tab; tabs are opened from it
+	cancel  context.CancelFunc
+	lost    <-chan struct{} // Closed when the connection to the browser drops
+	tabs    int
+	pages   int
+	retired bool // No new tabs; shut down once the open ones close
+}
+
+// NewBrowserPool creates a pool that starts browsers with newAllocator on
+// first use. remote marks an allocator that connects to a browser running
+// elsewhere. maxTabs below 1 means a single tab; recycleAfter 0 never
+// recycles; a healthInterval of 0 disables health checks.
+func NewBrowserPool(newAllocator func() (context.Context, context.CancelFunc), remote bool, maxTabs int, queueTimeout time.Duration, recycleAfter int, healthInterval time.Duration) *BrowserPool {
+	p := &BrowserPool{
+		newAllocator: newAllocator,
+		remote:       remote,
+		slots:        make(chan struct{}, max(maxTabs, 1)),
+		queueTimeout: queueTimeout,
+		recycleAfter: recycleAfter,
+	}
+
+	p.ctx, p.stop = context.WithCancel(context.Background())
+	if healthInterval > 0 {
+		go p.healthLoop(healthInterval)
+	}
+	return p
+}
+
+// Acquire opens a tab, waiting for a free slot up to the queue timeout.
+// The caller must call release when done with the tab.
+func (p *BrowserPool) Acquire(ctx context.Context) (tabCtx context.Context, release func(), err error) {
+	queueCtx, cancel := context.WithTimeout(ctx, p.queueTimeout)
+	defer cancel()
+	select {
+	case p.slots <- struct{}{}:
+	case <-queueCtx.Done():
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, nil, ErrBrowserBusy
+	}
+
+	inst, err := p.instance(ctx, true)
+	if err != nil {
+		<-p.slots
+		return nil, nil, err
+	}
+
+	tabCtx, closeTab := chromedp.NewContext(inst.ctx)
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			closeTab()
+			p.mu.Lock()
+			inst.tabs--
+			if inst.retired && inst.tabs == 0 {
+				inst.cancel()
+			}
+			p.mu.Unlock()
+			<-p.slots
+		})
+	}
+	return tabCtx, release, nil
+}
+
+// Stats reports the pool's current state
+func (p *BrowserPool) Stats() BrowserPoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := BrowserPoolStats{
+		Mode:     "local",
+		State:    BrowserIdle,
+		MaxTabs:  cap(p.slots),
+		OpenTabs: len(p.slots),
+		Restarts: p.restarts,
+		Recycled: p.recycled,
+	}
+	if p.remote {
+		stats.Mode = "remote"
+	}
+	if p.lastErr != nil {
+		stats.LastError = p.lastErr.Error()
+	}
+	switch {
+	case p.current != nil && !p.current.dead():
+		stats.State = BrowserConnected
+		stats.Running = true
+		stats.Pages = p.current.pages
+	case p.launching != nil && p.started:
+		stats.State = BrowserReconnecting
+	case p.launching != nil:
+		stats.State = BrowserStarting
+	case p.started || p.lastErr != nil:
+		stats.State = BrowserDisconnected
+	}
+	return stats
+}
+
+// Close shuts down the browser. Open tabs are closed with it.
+func (p *BrowserPool) Close() {
+	p.stop()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	if p.current != nil {
+		p.current.cancel()
+		p.current = nil
+	}
+}
+
+// instance returns the browser new tabs should open in, replacing a dead
+// browser or one that has served its page quota. With claim set, a tab is
+// counted against it. A browser is launched without holding p.mu, once for
+// all callers waiting on it; each caller waits only as long as its ctx allows.
+func (p *BrowserPool) instance(ctx context.Context, claim bool) (*browserInstance, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrBrowserClosed
+		}
+		if inst := p.current; inst != nil {
+			switch {
+			case inst.dead():
+				p.restarts++
+				p.retire(inst)
+			case p.recycleAfter > 0 && inst.pages >= p.recycleAfter:
+				p.recycled++
+				p.retire(inst)
+			default:
+				if claim {
+					inst.tabs++
+					inst.pages++
+				}
+				p.mu.Unlock()
+				return inst, nil
+			}
+		}
+
+		launch := p.launching
+		if launch == nil {
+			launch = &browserLaunch{done: make(chan struct{})}
+			p.launching = launch
+			go p.launch(launch)
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-launch.done:
+			if launch.err != nil {
+				return nil, launch.err
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// launch starts a browser and makes it current, recording why it failed otherwise
+func (p *BrowserPool) launch(launch *browserLaunch) {
+	inst, err := p.start()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer close(launch.done)
+	p.launching = nil
+	switch {
+	case err != nil:
+		p.lastErr = err
+		launch.err = err
+	case p.closed:
+		inst.cancel()
+		launch.err = ErrBrowserClosed
+	default:
+		p.current = inst
+		p.started = true
+		p.lastErr = nil
+		go p.watch(inst)
+	}
+}
+
+// start launches a browser, or dials a remote one, and waits until it is
+// ready for tabs. It gives up after browserStartTimeout or when the pool closes.
+func (p *BrowserPool) start() (*browserInstance, error) {
+	allocCtx, cancelAlloc := p.newAllocator()
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	cancel := func() {
+		cancelBrowser()
+		cancelAlloc()
+	}
+
+	// The first Run starts the browser and ties it to browserCtx, so it must
+	// not carry a timeout; the wait for it is bounded instead
+	started := make(chan error, 1)
+	go func() {
+		started <- chromedp.Run(browserCtx)
+	}()
+	timer := time.NewTimer(browserStartTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-started:
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+	case <-timer.C:
+		cancel()
+		return nil, fmt.Errorf("browser did not start within %v", browserStartTimeout)
+	case <-p.ctx.Done():
+		cancel()
+		return nil, ErrBrowserClosed
+	}
+
+	return &browserInstance{
+		ctx:    browserCtx,
+		cancel: cancel,
+		lost:   chromedp.FromContext(browserCtx).Browser.LostConnection,
+	}, nil
+}
+
+// watch notices a browser that goes away on its own and brings up a new one
+func (p *BrowserPool) watch(inst *browserInstance) {
+	select {
+	case <-inst.lost:
+	case <-inst.ctx.Done():
+		return
+	}
+
+	p.mu.Lock()
+	crashed := !inst.retired && !p.closed
+	if crashed {
+		p.lastErr = errors.New("connection to the browser was lost")
+	}
+	p.mu.Unlock()
+	inst.cancel()
+	if crashed {
+		log.Println("Browser connection lost, reconnecting")
+		p.reconnect()
+	}
+}
+
+// Connect brings the browser up in the background, so it is ready before the
+// first page and its connection state is known
+func (p *BrowserPool) Connect() {
+	go p.reconnect()
+}
+
+// reconnect brings up a browser if none is running, backing off between
+// failed attempts until one is up or the pool closes. Only one runs at a time.
+func (p *BrowserPool) reconnect() {
+	p.mu.Lock()
+	if p.reconnecting {
+		p.mu.Unlock()
+		return
+	}
+	p.reconnecting = true
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.reconnecting = false
+		p.mu.Unlock()
+	}()
+
+	delay := reconnectMinDelay
+	for {
+		_, err := p.instance(p.ctx, false)
+		if err == nil {
+			log.Println("Browser connected")
+			return
+		}
+		if errors.Is(err, ErrBrowserClosed) {
+			return
+		}
+
+		log.Printf("Browser connection failed, retrying in %v: %v", delay, err)
+		if sleepContext(p.ctx, delay) != nil {
+			return
+		}
+		delay = min(delay*2, reconnectMaxDelay)
+	}
+}
+
+// retire stops new tabs opening in inst and shuts it down once its open tabs
+// close. Call with p.mu held.
+func (p *BrowserPool) retire(inst *browserInstance) {
+	inst.retired = true
+	if inst.tabs == 0 || inst.dead() {
+		inst.cancel()
+	}
+	if p.current == inst {
+		p.current = nil
+	}
+}
+
+// healthLoop pings the browser every interval and replaces it when it does not answer
+func (p *BrowserPool) healthLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		p.mu.Lock()
+		inst := p.current
+		p.mu.Unlock()
+		if inst == nil || inst.dead() {
+			continue
+		}
+
+		if err := inst.ping(p.ctx); err != nil && p.ctx.Err() == nil {
+			log.Printf("Browser failed its health check, restarting: %v", err)
+			p.mu.Lock()
+			if p.current == inst {
+				p.restarts++
+				p.lastErr = fmt.Errorf("health check failed: %w", err)
+				inst.retired = true
+				inst.cancel()
+				p.current = nil
+			}
+			p.mu.Unlock()
+			go p.reconnect()
+		}
+	}
+}
+
+// ping asks the browser for its version
+func (inst *browserInstance) ping(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	executor := chromedp.FromContext(inst.ctx).Browser
+	_, _, _, _, _, err := browser.GetVersion().Do(cdp.WithExecutor(pingCtx, executor))
+	return err
+}
+
+// dead reports whether the browser has exited or its connection dropped
+func (inst *browserInstance) dead() bool {
+	if inst.ctx.Err() != nil {
+		return true
+	}
+	select {
+	case <-inst.lost:
+		return true
+	default:
+		return false
+	}
+}
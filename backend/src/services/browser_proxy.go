@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"time"
+)
+
+// proxyDialTimeout bounds connecting to a target through the browser proxy
+const proxyDialTimeout = 30 * time.Second
+
+// BrowserProxy is a forward proxy that a launched browser sends all its
+// traffic through. Every connection is dialed through the URL guard, which
+// covers what Fetch interception cannot: WebSocket handshakes never pause in
+// the Fetch domain, and a host that resolves to a public address when a
+// request is vetted can rebind to a private one before the browser connects.
+type BrowserProxy struct {
+	guard    *URLGuard
+	listener net.Listener
+	server   *http.Server
+	forward  *httputil.ReverseProxy
+}
+
+// NewBrowserProxy starts a proxy for the guard on a loopback port
+func NewBrowserProxy(guard *URLGuard) (*BrowserProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	transport := guard.Transport()
+	// Chrome already decides proxying; the guard must see the target itself
+	transport.Proxy = nil
+
+	p := &BrowserProxy{guard: guard, listener: listener}
+	p.forward = &httputil.ReverseProxy{
+		// Proxy requests carry an absolute URL, which is already where they go
+		Rewrite:      func(r *httputil.ProxyRequest) {},
+		Transport:    transport,
+		ErrorHandler: p.fail,
+	}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: proxyDialTimeout}
+	go func() {
+		if err := p.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Browser proxy stopped: %v", err)
+		}
+	}()
+	return p, nil
+}
+
+// URL returns the address browsers are pointed at with --proxy-server
+func (p *BrowserProxy) URL() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// Close stops the proxy, dropping open tunnels
+func (p *BrowserProxy) Close() {
+	p.server.Close()
+}
+
+// ServeHTTP tunnels CONNECT requests (HTTPS and WebSockets) and forwards
+// plain HTTP requests, refusing targets the guard forbids with a 403
+func (p *BrowserProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "only proxy requests are accepted", http.StatusBadRequest)
+		return
+	}
+	p.forward.ServeHTTP(w, r)
+}
+
+// tunnel connects to the CONNECT target and copies bytes both ways
+func (p *BrowserProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	dialer := p.guard.dialer()
+	dialer.Timeout = proxyDialTimeout
+	target, err := dialer.DialContext(r.Context(), "tcp", r.Host)
+	if err != nil {
+		p.fail(w, r, err)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		target.Close()
+		http.Error(w, "tunnelling is not supported", http.StatusInternalServerError)
+		return
+	}
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		target.Close()
+		return
+	}
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		client.Close()
+		target.Close()
+		return
+	}
+
+	var once sync.Once
+	closeBoth := func() {
+		once.Do(func() {
+			client.Close()
+			target.Close()
+		})
+	}
+	go func() {
+		defer closeBoth()
+		io.Copy(target, buffered)
+	}()
+	defer closeBoth()
+	io.Copy(client, target)
+}
+
+// fail answers a request whose target could not be reached
+func (p *BrowserProxy) fail(w http.ResponseWriter, r *http.Request, err error) {
+	var blocked *BlockedTargetError
+	if errors.As(err, &blocked) {
+		http.Error(w, blocked.Error(), http.StatusForbidden)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadGateway)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+)
+
+// browserTask is the extra work requested in the browser tab for one page
+type browserTask struct {
+	screenshot *models.ScreenshotOptions // Capture the page after it renders
+	pdf        *models.PDFOptions        // Print the page after it renders
+	actions    []models.BrowserAction    // Scripted steps run before the HTML is captured
+	wait       models.WaitOptions        // How to wait for the page to load (default from config)
+	block      *models.BlockOptions      // Requests to fail instead of loading
+	har        bool                      // Record the tab's network traffic
+	captureAPI *models.APICaptureOptions // XHR and fetch responses to keep
+}
+
+// browserTask returns the tab work the run asks for on item. The wait,
+// blocking and API capture apply to every page; captures, actions and HAR
+// recording to the seed page only.
+func (s *ScraperService) browserTask(run *scrapeRun, item crawlItem) browserTask {
+	var task browserTask
+	if run.opts.Wait != nil {
+		task.wait = *run.opts.Wait
+	}
+	task.block = run.opts.Block
+	task.captureAPI = run.opts.CaptureAPI
+	if item.parentURL == "" {
+		task.screenshot = run.opts.Screenshot
+		task.actions = run.opts.Actions
+		task.har = run.opts.HAR
+	}
+	return task
+}
+
+// needsRender reports whether the task can only be done by rendering the
+// page in the browser now, so cached or static HTML will not do
+func (t browserTask) needsRender() bool {
+	return t.screenshot != nil || t.pdf != nil || len(t.actions) > 0 || t.har || t.captureAPI != nil
+}
+
+// renderForCapture renders targetURL in a fresh tab for a standalone capture.
+// The URL guard and robots.txt apply as for a scrape.
+func (s *ScraperService) renderForCapture(ctx context.Context, targetURL string, task browserTask) (*pageFetch, error) {
+	if err := s.guard.CheckURL(ctx, targetURL); err != nil {
+		return nil, err
+	}
+	userAgent := s.userAgents.Next(hostOf(targetURL))
+	if _, err := s.checkRobots(ctx, targetURL, userAgent); err != nil {
+		return nil, err
+	}
+
+	fetch, err := s.fetchWithChromedp(ctx, targetURL, userAgent, task)
+	if err != nil {
+		return nil, fmt.Errorf("rendering failed: %w", err)
+	}
+	return fetch, nil
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/extract"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+)
+
+// partialMarkdownLength is how much of a page's Markdown is included in page events
+const partialMarkdownLength = 2000
+
+// errNotRendered explains browser captures missing from pages fetched statically
+var errNotRendered = errors.New("the page was not rendered in the browser")
+
+// EventFunc receives events while a scrape runs
+type EventFunc func(event models.ScrapeEvent)
+
+// crawlItem is a URL waiting in the crawl frontier
+type crawlItem struct {
+	url       string
+	parentURL string
+	depth     int
+}
+
+// scrapeRun carries the state of a single scrape through the crawl
+type scrapeRun struct {
+	opts   models.ScrapeOptions
+	schema *extract.Schema // Compiled opts.Extract, if any
+	result *models.ScrapeResult
+	emit   EventFunc
+	queued int
+}
+
+// event forwards an event to the listener, if any
+func (r *scrapeRun) event(event models.ScrapeEvent) {
+	if r.emit != nil {
+		r.emit(event)
+	}
+}
+
+// progress returns a snapshot of the crawl progress
+func (r *scrapeRun) progress(currentURL string) *models.CrawlProgress {
+	return &models.CrawlProgress{
+		PagesVisited: len(r.result.Pages),
+		PagesQueued:  r.queued,
+		MaxPages:     r.opts.MaxPages,
+		CurrentURL:   currentURL,
+	}
+}
+
+// warn records a warning, prefixing it with the URL for pages beyond the seed
+func (r *scrapeRun) warn(item crawlItem, message string) {
+	if item.parentURL != "" {
+		message = fmt.Sprintf("%s: %s", item.url, message)
+	}
+	r.result.Warnings = append(r.result.Warnings, message)
+}
+
+// captureWarning records why a browser capture the request asked for is
+// missing or incomplete: its own error, or that the page never reached the browser
+func (r *scrapeRun) captureWarning(item crawlItem, fetch *pageFetch, name string, requested bool, err error) {
+	if !requested {
+		return
+	}
+	if err == nil && fetch.mode != models.RenderModeBrowser {
+		err = errNotRendered
+	}
+	if err != nil {
+		r.warn(item, fmt.Sprintf("%s: %v", name, err))
+	}
+}
+
+// fallback records a warning about switching fetchers and emits a fallback event
+func (r *scrapeRun) fallback(item crawlItem, message string) {
+	r.warn(item, message)
+	r.event(models.ScrapeEvent{Type: models.EventFallback, URL: item.url, Depth: item.depth, Message: message})
+}
+
+// crawl visits pages breadth-first from the seed URL, following same-host links
+// until the depth or page limits are reached. It returns the raw HTML of the seed page.
+func (s *ScraperService) crawl(ctx context.Context, seed *url.URL, run *scrapeRun) (string, error) {
+	opts, result := run.opts, run.result
+	seed = stripFragment(seed)
+	queue := []crawlItem{{url: seed.String(), depth: 1}}
+	seen := map[string]bool{crawlKey(seed): true}
+
+	var seedHTML string
+	crawlDelayNoted := false
+	for len(queue) > 0 && len(result.Pages) < opts.MaxPages {
+		item := queue[0]
+		queue = queue[1:]
+		run.queued = len(queue)
+
+		// Refuse private, loopback and metadata targets before anything is fetched
+		if err := s.guard.CheckURL(ctx, item.url); err != nil {
+			if len(result.Pages) == 0 {
+				return "", err
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", item.url, err))
+			continue
+		}
+
+		// Pick the user agent once so robots.txt and both fetchers agree
+		userAgent := s.userAgents.Next(hostOf(item.url))
+
+		// Check robots.txt before either fetcher touches the page
+		crawlDelay, err := s.checkRobots(ctx, item.url, userAgent)
+		if err != nil {
+			// A disallowed seed page fails the whole scrape
+			if len(result.Pages) == 0 {
+				return "", err
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", item.url, err))
+			continue
+		}
+
+		// Be polite between consecutive page fetches, honoring Crawl-delay
+		delay := s.config.GetScraperDelay()
+		if crawlDelay > delay {
+			if !crawlDelayNoted {
+				crawlDelayNoted = true
+				result.Warnings = append(result.Warnings, fmt.Sprintf("robots.txt Crawl-delay of %s applied between page fetches", crawlDelay))
+			}
+			delay = crawlDelay
+		}
+		// Pages answered from the cache never reach the site, so they need no delay
+		if len(result.Pages) > 0 && !s.servesFromCache(run, item) {
+			if err := sleepContext(ctx, delay); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Crawl stopped early after %d pages: %v", len(result.Pages), err))
+				break
+			}
+		}
+
+		run.event(models.ScrapeEvent{
+			Type:     models.EventFetchStarted,
+			URL:      item.url,
+			Depth:    item.depth,
+			Progress: run.progress(item.url),
+		})
+		page, html, err := s.scrapePage(ctx, run, item, userAgent)
+		if err != nil {
+			// Failing to fetch the seed page fails the whole scrape
+			if len(result.Pages) == 0 {
+				return "", err
+			}
+			log.Printf("Crawl skipped %s: %v", item.url, err)
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", item.url, err))
+			if ctx.Err() != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Crawl stopped early after %d pages: %v", len(result.Pages), ctx.Err()))
+				break
+			}
+			continue
+		}
+
+		if len(result.Pages) == 0 {
+			seedHTML = html
+		}
+		result.Pages = append(result.Pages, *page)
+
+		if item.depth < opts.Depth {
+			// Enqueue unseen same-host links for the next level
+			for _, link := range page.Links {
+				next, ok := crawlCandidate(seed, link.Href)
+				if !ok {
+					continue
+				}
+				key := crawlKey(next)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				queue = append(queue, crawlItem{
+					url:       next.String(),
+					parentURL: item.url,
+					depth:     item.depth + 1,
+				})
+			}
+		}
+		run.queued = len(queue)
+
+		markdown, truncated := truncateRunes(page.Markdown, partialMarkdownLength)
+		run.event(models.ScrapeEvent{
+			Type:              models.EventPageCompleted,
+			URL:               page.URL,
+			Depth:             page.Depth,
+			Mode:              page.Mode,
+			Title:             page.Title,
+			Markdown:          markdown,
+			MarkdownTruncated: truncated,
+			Progress:          run.progress(""),
+		})
+		run.event(models.ScrapeEvent{Type: models.EventLinks, URL: page.URL, Depth: page.Depth, Links: page.Links})
+	}
+
+	if len(queue) > 0 && len(result.Pages) >= opts.MaxPages {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Crawl reached the limit of %d pages, %d URLs not visited", opts.MaxPages, len(queue)))
+	}
+
+	return seedHTML, nil
+}
+
+// checkRobots enforces robots.txt for rawURL unless disabled in config.
+// It returns the Crawl-delay the site requests for our user agent.
+func (s *ScraperService) checkRobots(ctx context.Context, rawURL, userAgent string) (time.Duration, error) {
+	if s.config.IgnoreRobotsTxt {
+		return 0, nil
+	}
+
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	return s.robots.Check(ctx, target, userAgent)
+}
+
+// crawlCandidate reports whether href should be followed from the seed URL
+func crawlCandidate(seed *url.URL, href string) (*url.URL, bool) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return nil, false
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, false
+	}
+	if !strings.EqualFold(u.Hostname(), seed.Hostname()) {
+		return nil, false
+	}
+	return stripFragment(u), true
+}
+
+// crawlKey normalizes a URL for de-duplication in the crawl frontier
+func crawlKey(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	key := strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + path
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+// hostOf returns the host of rawURL, or an empty string if it cannot be parsed
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// stripFragment returns a copy of u without its #fragment
+func stripFragment(u *url.URL) *url.URL {
+	clean := *u
+	clean.Fragment = ""
+	clean.RawFragment = ""
+	return &clean
+}
+
+// truncateRunes shortens s to at most n runes and reports whether it was cut
+func truncateRunes(s string, n int) (string, bool) {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s, false
+	}
+	return string(runes[:n]), true
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// maxHAREntries caps how many requests one archive records
+	maxHAREntries = 5000
+	// harPageID identifies the recorded page in the archive
+	harPageID = "page_1"
+)
+
+// harRecorder records a tab's network traffic as HAR entries
+type harRecorder struct {
+	mu        sync.Mutex
+	entries   []*harEntry
+	open      map[network.RequestID]*harEntry // Latest entry per request; redirects reuse the ID
+	dropped   int
+	started   time.Time // Wall time of the first request
+	startedAt time.Time // Monotonic time of the first request
+	onContent time.Time // Monotonic time DOMContentLoaded fired
+	onLoad    time.Time // Monotonic time load fired
+}
+
+// harEntry is an entry being recorded, with the raw times its timings derive from
+type harEntry struct {
+	entry      models.HAREntry
+	sent       time.Time // Monotonic time the request was issued
+	responseAt time.Time // Monotonic time the response headers arrived
+	finished   time.Time // Monotonic time loading finished or failed
+	timing     *network.ResourceTiming
+}
+
+// newHARRecorder creates a recorder for a single tab
+func newHARRecorder() *harRecorder {
+	return &harRecorder{open: make(map[network.RequestID]*harEntry)}
+}
+
+// listen attaches the recorder to the tab in ctx. Call it before the first Run.
+func (r *harRecorder) listen(ctx context.Context) {
+	chromedp.ListenTarget(ctx, func(ev any) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		switch ev := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			r.requestSent(ev)
+		case *network.EventResponseReceived:
+			if e := r.open[ev.RequestID]; e != nil {
+				e.setResponse(ev.Response, monotonicTime(ev.Timestamp))
+				e.entry.ResourceType = strings.ToLower(string(ev.Type))
+			}
+		case *network.EventDataReceived:
+			if e := r.open[ev.RequestID]; e != nil {
+				e.entry.Response.Content.Size += int(ev.DataLength)
+			}
+		case *network.EventLoadingFinished:
+			if e := r.open[ev.RequestID]; e != nil {
+				e.finished = monotonicTime(ev.Timestamp)
+				e.entry.Response.BodySize = int(ev.EncodedDataLength)
+				delete(r.open, ev.RequestID)
+			}
+		case *network.EventLoadingFailed:
+			if e := r.open[ev.RequestID]; e != nil {
+				e.finished = monotonicTime(ev.Timestamp)
+				e.entry.Error = ev.ErrorText
+				if ev.BlockedReason != "" {
+					e.entry.Error += " (" + string(ev.BlockedReason) + ")"
+				}
+				delete(r.open, ev.RequestID)
+			}
+		case *page.EventDomContentEventFired:
+			if r.onContent.IsZero() {
+				r.onContent = monotonicTime(ev.Timestamp)
+			}
+		case *page.EventLoadEventFired:
+			if r.onLoad.IsZero() {
+				r.onLoad = monotonicTime(ev.Timestamp)
+			}
+		}
+	})
+}
+
+// requestSent starts an entry, completing the previous hop first on a redirect.
+// Call with r.mu held.
+func (r *harRecorder) requestSent(ev *network.EventRequestWillBeSent) {
+	sent := monotonicTime(ev.Timestamp)
+	if prev := r.open[ev.RequestID]; prev != nil && ev.RedirectResponse != nil {
+		prev.setResponse(ev.RedirectResponse, sent)
+		prev.entry.Response.RedirectURL = ev.Request.URL
+		prev.finished = sent
+		delete(r.open, ev.RequestID)
+	}
+
+	if len(r.entries) >= maxHAREntries {
+		r.dropped++
+		return
+	}
+
+	wall := time.Now()
+	if ev.WallTime != nil {
+		wall = ev.WallTime.Time()
+	}
+	if r.started.IsZero() {
+		r.started, r.startedAt = wall, sent
+	}
+
+	e := &harEntry{sent: sent}
+	e.entry = models.HAREntry{
+		PageRef:         harPageID,
+		StartedDateTime: wall.UTC().Format(time.RFC3339Nano),
+		Request:         harRequest(ev.Request),
+		Response: models.HARResponse{
+			Cookies:     []models.HARCookie{},
+			Headers:     []models.HARNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		ResourceType: strings.ToLower(string(ev.Type)),
+	}
+	r.entries = append(r.entries, e)
+	r.open[ev.RequestID] = e
+}
+
+// build returns the archive recorded so far. Requests still in flight are
+// included without a response.
+func (r *harRecorder) build(pageURL string) *models.HAR {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	har := &models.HAR{Log: models.HARLog{
+		Version: "1.2",
+		Creator: models.HARCreator{Name: "web-scraper-backend", Version: "1.0"},
+		Pages: []models.HARPage{{
+			StartedDateTime: r.started.UTC().Format(time.RFC3339Nano),
+			ID:              harPageID,
+			Title:           pageURL,
+			PageTimings: models.HARPageTimings{
+				OnContentLoad: sinceOrUnset(r.startedAt, r.onContent),
+				OnLoad:        sinceOrUnset(r.startedAt, r.onLoad),
+			},
+		}},
+		Entries: make([]models.HAREntry, 0, len(r.entries)),
+	}}
+	if r.dropped > 0 {
+		har.Log.Comment = fmt.Sprintf("%d requests past the first %d were not recorded", r.dropped, maxHAREntries)
+	}
+
+	for _, e := range r.entries {
+		entry := e.entry
+		entry.Timings = e.timings()
+		for _, phase := range []float64{entry.Timings.Blocked, entry.Timings.DNS, entry.Timings.Connect,
+			entry.Timings.Send, entry.Timings.Wait, entry.Timings.Receive} {
+			entry.Time += max(phase, 0)
+		}
+		har.Log.Entries = append(har.Log.Entries, entry)
+	}
+	return har
+}
+
+// setResponse records the response headers for the entry
+func (e *harEntry) setResponse(resp *network.Response, at time.Time) {
+	e.responseAt = at
+	e.timing = resp.Timing
+
+	httpVersion := harHTTPVersion(resp.Protocol)
+	e.entry.Request.HTTPVersion = httpVersion
+	if len(resp.RequestHeaders) > 0 {
+		e.entry.Request.Headers = harHeaders(resp.RequestHeaders)
+	}
+
+	e.entry.Response.Status = int(resp.Status)
+	e.entry.Response.StatusText = resp.StatusText
+	e.entry.Response.HTTPVersion = httpVersion
+	e.entry.Response.Headers = harHeaders(resp.Headers)
+	e.entry.Response.Content.MimeType = resp.MimeType
+	e.entry.Response.RedirectURL = headerValue(resp.Headers, "Location")
+	e.entry.ServerIPAddress = strings.Trim(resp.RemoteIPAddress, "[]")
+}
+
+// timings splits the entry's duration into HAR phases. Chrome's resource
+// timing gives the connection phases when available; otherwise only the
+// wait for headers and the body download are known.
+func (e *harEntry) timings() models.HARTimings {
+	t := models.HARTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1}
+	end := e.finished
+	if end.IsZero() {
+		end = e.responseAt
+	}
+	if e.responseAt.IsZero() {
+		if !end.IsZero() {
+			t.Wait = milliseconds(end.Sub(e.sent))
+		}
+		return t
+	}
+
+	tm := e.timing
+	if tm == nil {
+		t.Wait = milliseconds(e.responseAt.Sub(e.sent))
+		t.Receive = max(milliseconds(end.Sub(e.responseAt)), 0)
+		return t
+	}
+
+	base := monotonicSeconds(tm.RequestTime)
+	t.Blocked = max(milliseconds(base.Sub(e.sent))+firstNonNegative(tm.DNSStart, tm.ConnectStart, tm.SendStart), 0)
+	if tm.DNSStart >= 0 {
+		t.DNS = tm.DNSEnd - tm.DNSStart
+	}
+	if tm.ConnectStart >= 0 {
+		t.Connect = tm.ConnectEnd - tm.ConnectStart
+	}
+	if tm.SslStart >= 0 {
+		t.SSL = tm.SslEnd - tm.SslStart
+	}
+	t.Send = max(tm.SendEnd-tm.SendStart, 0)
+	t.Wait = max(tm.ReceiveHeadersEnd-tm.SendEnd, 0)
+	t.Receive = max(milliseconds(end.Sub(base))-tm.ReceiveHeadersEnd, 0)
+	return t
+}
+
+// harRequest converts a CDP request into a HAR request
+func harRequest(req *network.Request) models.HARRequest {
+	out := models.HARRequest{
+		Method:      req.Method,
+		URL:         req.URL + req.URLFragment,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []models.HARCookie{},
+		Headers:     harHeaders(req.Headers),
+		QueryString: []models.HARNameValue{},
+		HeadersSize: -1,
+	}
+	if u, err := url.Parse(req.URL); err == nil {
+		for name, values := range u.Query() {
+			for _, value := range values {
+				out.QueryString = append(out.QueryString, models.HARNameValue{Name: name, Value: value})
+			}
+		}
+		sortNameValues(out.QueryString)
+	}
+
+	if req.HasPostData {
+		body := requestBody(req)
+		out.PostData = &models.HARPostData{MimeType: headerValue(req.Headers, "Content-Type"), Text: body}
+		out.BodySize = len(body)
+	}
+	return out
+}
+
+// harHeaders converts CDP headers into sorted HAR name/value pairs. Chrome
+// joins repeated headers such as Set-Cookie with newlines, so they are split.
+func harHeaders(headers network.Headers) []models.HARNameValue {
+	out := []models.HARNameValue{}
+	for name, value := range headers {
+		text, ok := value.(string)
+		if !ok {
+			text = fmt.Sprint(value)
+		}
+		for _, line := range strings.Split(text, "\n") {
+			out = append(out, models.HARNameValue{Name: name, Value: line})
+		}
+	}
+	sortNameValues(out)
+	return out
+}
+
+// sortNameValues orders pairs by name, keeping repeated names in their original order
+func sortNameValues(pairs []models.HARNameValue) {
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return strings.ToLower(pairs[i].Name) < strings.ToLower(pairs[j].Name)
+	})
+}
+
+// harHTTPVersion names the protocol Chrome reports the way HAR viewers expect
+func harHTTPVersion(protocol string) string {
+	switch strings.ToLower(protocol) {
+	case "":
+		return "HTTP/1.1"
+	case "h2":
+		return "HTTP/2"
+	case "h3", "quic":
+		return "HTTP/3"
+	}
+	return strings.ToUpper(protocol)
+}
+
+// monotonicTime converts a CDP timestamp, treating a missing one as now
+func monotonicTime(t *cdp.MonotonicTime) time.Time {
+	if t == nil {
+		return time.Now()
+	}
+	return t.Time()
+}
+
+// monotonicSeconds converts a resource timing baseline to a CDP monotonic time
+func monotonicSeconds(seconds float64) time.Time {
+	return cdp.MonotonicTimeEpoch.Add(time.Duration(seconds * float64(time.Second)))
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// sinceOrUnset returns the milliseconds from start to t, or -1 when t never happened
+func sinceOrUnset(start, t time.Time) float64 {
+	if start.IsZero() || t.IsZero() {
+		return -1
+	}
+	return milliseconds(t.Sub(start))
+}
+
+// firstNonNegative returns the first value that is not negative, or 0
+func firstNonNegative(values ...float64) float64 {
+	for _, v := range values {
+		if v >= 0 {
+			return v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/config"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+)
+
+var (
+	// ErrJobNotFound is returned when a job ID is unknown or has expired
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobQueueFull is returned when the job queue cannot accept more work
+	ErrJobQueueFull = errors.New("job queue is full")
+	// ErrJobServiceClosed is returned when jobs are submitted during shutdown
+	ErrJobServiceClosed = errors.New("job service is shutting down")
+)
+
+// jobEntry is the service's mutable record of a job
+type jobEntry struct {
+	job    models.Job
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// JobService runs scrapes asynchronously on a bounded worker pool
+type JobService struct {
+	scraper *ScraperService
+	ttl     time.Duration
+
+	queue   chan *jobEntry
+	baseCtx context.Context
+	stopAll context.CancelFunc
+	workers sync.WaitGroup
+	closing chan struct{} // Closed on Shutdown to stop the eviction loop
+
+	mu     sync.RWMutex
+	jobs   map[string]*jobEntry
+	closed bool
+}
+
+// NewJobService creates a job service and starts its workers
+func NewJobService(cfg *config.Config, scraper *ScraperService) *JobService {
+	workers := max(cfg.JobWorkers, 1)
+	baseCtx, stopAll := context.WithCancel(context.Background())
+
+	s := &JobService{
+		scraper: scraper,
+		ttl:     cfg.GetJobTTL(),
+		queue:   make(chan *jobEntry, max(cfg.JobQueueSize, 1)),
+		baseCtx: baseCtx,
+		stopAll: stopAll,
+		jobs:    make(map[string]*jobEntry),
+		closing: make(chan struct{}),
+	}
+
+	for i := 0; i < workers; i++ {
+		s.workers.Add(1)
+		go s.worker()
+	}
+	if s.ttl > 0 {
+		go s.evictLoop()
+	}
+
+	return s
+}
+
+// Submit enqueues a scrape and returns the queued job
+func (s *JobService) Submit(req models.ScrapeRequest) (models.Job, error) {
+	id, err := newRandomID()
+	if err != nil {
+		return models.Job{}, err
+	}
+
+	ctx, cancel := context.WithCancel(s.baseCtx)
+	entry := &jobEntry{
+		job: models.Job{
+			ID:        id,
+			Request:   req,
+			Status:    models.JobStatusQueued,
+			Progress:  models.CrawlProgress{MaxPages: req.MaxPages},
+			CreatedAt: time.Now(),
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		cancel()
+		return models.Job{}, ErrJobServiceClosed
+	}
+	s.evictExpiredLocked()
+
+	select {
+	case s.queue <- entry:
+	default:
+		cancel()
+		return models.Job{}, ErrJobQueueFull
+	}
+	s.jobs[id] = entry
+
+	return entry.job, nil
+}
+
+// Get returns a snapshot of the job with the given ID
+func (s *JobService) Get(id string) (models.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.jobs[id]
+	if !ok || s.expired(entry, time.Now()) {
+		return models.Job{}, ErrJobNotFound
+	}
+	return entry.job, nil
+}
+
+// Cancel stops a queued or running job. Cancelling a finished job is a no-op.
+func (s *JobService) Cancel(id string) (models.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.jobs[id]
+	if !ok {
+		return models.Job{}, ErrJobNotFound
+	}
+
+	if entry.job.Status == models.JobStatusQueued {
+		// The worker skips cancelled entries when it dequeues them
+		s.finishLocked(entry, models.JobStatusCancelled, nil, context.Canceled)
+	}
+	entry.cancel()
+
+	return entry.job, nil
+}
+
+// Shutdown stops accepting jobs and waits for queued and running jobs to finish.
+// When ctx expires first, the remaining jobs are cancelled.
+func (s *JobService) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+		close(s.closing)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		log.Printf("Job drain timed out, cancelling remaining jobs")
+		s.stopAll()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// worker runs jobs from the queue until it is closed
+func (s *JobService) worker() {
+	defer s.workers.Done()
+	for entry := range s.queue {
+		s.run(entry)
+	}
+}
+
+// run executes a single job and records its outcome
+func (s *JobService) run(entry *jobEntry) {
+	s.mu.Lock()
+	if entry.job.Status != models.JobStatusQueued || entry.ctx.Err() != nil {
+		s.finishLocked(entry, models.JobStatusCancelled, nil, entry.ctx.Err())
+		s.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	entry.job.Status = models.JobStatusRunning
+	entry.job.StartedAt = &now
+	s.mu.Unlock()
+
+	req := entry.job.Request
+	result, err := s.scraper.ScrapeWithEvents(entry.ctx, req.URL, req.ScrapeOptions, func(event models.ScrapeEvent) {
+		if event.Progress == nil {
+			return
+		}
+		s.mu.Lock()
+		entry.job.Progress = *event.Progress
+		s.mu.Unlock()
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case entry.ctx.Err() != nil:
+		s.finishLocked(entry, models.JobStatusCancelled, result, entry.ctx.Err())
+	case err != nil:
+		log.Printf("Job %s failed: %v", entry.job.ID, err)
+		s.finishLocked(entry, models.JobStatusFailed, nil, err)
+	default:
+		s.finishLocked(entry, models.JobStatusSucceeded, result, nil)
+	}
+	entry.cancel()
+}
+
+// finishLocked moves a job into a terminal state. The caller must hold s.mu.
+func (s *JobService) finishLocked(entry *jobEntry, status models.JobStatus, result *models.ScrapeResult, err error) {
+	if entry.job.Status.Finished() {
+		return
+	}
+	now := time.Now()
+	entry.job.Status = status
+	entry.job.Result = result
+	entry.job.FinishedAt = &now
+	if err != nil {
+		entry.job.Error = err.Error()
+	}
+	if result != nil {
+		entry.job.Progress.PagesVisited = len(result.Pages)
+		entry.job.Progress.CurrentURL = ""
+	}
+}
+
+// evictLoop drops expired jobs periodically, so finished results are freed
+// even when no new jobs arrive
+func (s *JobService) evictLoop() {
+	ticker := time.NewTicker(min(s.ttl, time.Minute))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+		}
+		s.mu.Lock()
+		s.evictExpiredLocked()
+		s.mu.Unlock()
+	}
+}
+
+// evictExpiredLocked drops finished jobs older than the TTL. The caller must hold s.mu.
+func (s *JobService) evictExpiredLocked() {
+	now := time.Now()
+	for id, entry := range s.jobs {
+		if s.expired(entry, now) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// expired reports whether a job finished more than the TTL before now
+func (s *JobService) expired(entry *jobEntry, now time.Time) bool {
+	return s.ttl > 0 && entry.job.FinishedAt != nil && entry.job.FinishedAt.Before(now.Add(-s.ttl))
+}
+
+// newRandomID returns a random 128-bit hex identifier for jobs and stored results
+func newRandomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
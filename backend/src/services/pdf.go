@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// emptyTemplate suppresses Chrome's default header or footer when only the other one is given
+const emptyTemplate = "<span></span>"
+
+// PDF renders targetURL in the browser and prints it to PDF as opts asks.
+// The URL guard and robots.txt apply as for a scrape; the page is not
+// converted, cached or saved to history.
+func (s *ScraperService) PDF(ctx context.Context, targetURL string, opts models.PDFOptions) ([]byte, error) {
+	fetch, err := s.renderForCapture(ctx, targetURL, browserTask{pdf: &opts})
+	if err != nil {
+		return nil, err
+	}
+	if fetch.pdf == nil {
+		return nil, fmt.Errorf("printing failed: %w", fetch.pdfErr)
+	}
+	return fetch.pdf, nil
+}
+
+// printPDF prints the current page in the tab behind ctx
+func printPDF(ctx context.Context, opts models.PDFOptions) ([]byte, error) {
+	params := page.PrintToPDF().
+		WithLandscape(opts.Landscape).
+		WithPrintBackground(opts.Background).
+		WithPreferCSSPageSize(false)
+
+	width, height := opts.Width, opts.Height
+	if opts.Paper != "" {
+		width, height, _ = opts.Paper.Dimensions()
+	}
+	if width > 0 && height > 0 {
+		params = params.WithPaperWidth(width).WithPaperHeight(height)
+	}
+
+	if opts.MarginTop != nil {
+		params = params.WithMarginTop(*opts.MarginTop)
+	}
+	if opts.MarginBottom != nil {
+		params = params.WithMarginBottom(*opts.MarginBottom)
+	}
+	if opts.MarginLeft != nil {
+		params = params.WithMarginLeft(*opts.MarginLeft)
+	}
+	if opts.MarginRight != nil {
+		params = params.WithMarginRight(*opts.MarginRight)
+	}
+
+	if opts.HeaderTemplate != "" || opts.FooterTemplate != "" {
+		header, footer := opts.HeaderTemplate, opts.FooterTemplate
+		if header == "" {
+			header = emptyTemplate
+		}
+		if footer == "" {
+			footer = emptyTemplate
+		}
+		params = params.WithDisplayHeaderFooter(true).WithHeaderTemplate(header).WithFooterTemplate(footer)
+	}
+
+	var data []byte
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		data, _, err = params.Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("browser returned an empty document")
+	}
+	return data, nil
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// minStaticTextLength is the amount of visible body text below which a static page
+// that loads scripts is considered an unrendered JavaScript shell
+const minStaticTextLength = 200
+
+// frameworkRootSelectors match the mount points of common client-side frameworks
+var frameworkRootSelectors = []string{
+	"#root",
+	"#app",
+	"#__next",
+	"#__nuxt",
+	"#svelte",
+	"#___gatsby",
+	"[data-reactroot]",
+	"[ng-app]",
+	"app-root",
+}
+
+// noscriptWarnings are phrases sites use to tell visitors JavaScript is required
+var noscriptWarnings = []string{
+	"enable javascript",
+	"javascript is disabled",
+	"javascript is required",
+	"requires javascript",
+	"need to enable javascript",
+	"javascript must be enabled",
+}
+
+// fetchPage fetches a page with the requested render mode, switching to the
+// browser when the page has tab work such as a screenshot.
+// The returned fetch records the mode that actually produced the HTML.
+func (s *ScraperService) fetchPage(ctx context.Context, run *scrapeRun, item crawlItem, userAgent string) (*pageFetch, error) {
+	task := s.browserTask(run, item)
+	mode := run.opts.Mode
+	if task.needsRender() {
+		mode = models.RenderModeBrowser
+	}
+
+	switch mode {
+	case models.RenderModeStatic:
+		fetch, err := s.fetchWithColly(ctx, item.url, userAgent)
+		if err != nil {
+			log.Printf("Colly failed for %s: %v", item.url, err)
+			return nil, fmt.Errorf("scraping failed: %w", err)
+		}
+		return fetch, nil
+
+	case models.RenderModeBrowser:
+		// Try to fetch with Chromedp for JS-rendered content first
+		fetch, err := s.fetchWithChromedp(ctx, item.url, userAgent, task)
+		if err == nil {
+			return fetch, nil
+		}
+		log.Printf("Chromedp failed for %s: %v", item.url, err)
+		if isBlockedTarget(err) {
+			return nil, err
+		}
+		// Fallback to Colly if Chromedp fails, keeping what the tab reported
+		run.fallback(item, fmt.Sprintf("Chromedp failed (%v), falling back to static fetch", err))
+		browserLog := failedRenderLog(err)
+		fetch, err = s.fetchWithColly(ctx, item.url, userAgent)
+		if err != nil {
+			log.Printf("Colly also failed for %s: %v", item.url, err)
+			return nil, fmt.Errorf("scraping failed: %w", err)
+		}
+		fetch.browserLog = browserLog
+		fetch.fallback = true
+		return fetch, nil
+
+	default:
+		// Auto: fetch statically and only pay for the browser when the page needs it
+		fetch, err := s.fetchWithColly(ctx, item.url, userAgent)
+		var reason string
+		if err != nil {
+			log.Printf("Colly failed for %s: %v", item.url, err)
+			if isBlockedTarget(err) {
+				return nil, fmt.Errorf("scraping failed: %w", err)
+			}
+			reason = fmt.Sprintf("static fetch failed (%v)", err)
+		} else {
+			reason = jsShellReason(fetch.html)
+			if reason == "" {
+				return fetch, nil
+			}
+		}
+
+		log.Printf("Escalating %s to Chromedp: %s", item.url, reason)
+		browserFetch, browserErr := s.fetchWithChromedp(ctx, item.url, userAgent, task)
+		if browserErr == nil {
+			run.fallback(item, fmt.Sprintf("Rendered with browser: %s", reason))
+			return browserFetch, nil
+		}
+		log.Printf("Chromedp failed for %s: %v", item.url, browserErr)
+
+		if err != nil {
+			return nil, fmt.Errorf("scraping failed: %w", err)
+		}
+		run.fallback(item, fmt.Sprintf("Page looks JS-rendered (%s) but Chromedp failed (%v), using static HTML", reason, browserErr))
+		fetch.browserLog = failedRenderLog(browserErr)
+		fetch.fallback = true
+		return fetch, nil
+	}
+}
+
+// jsShellReason inspects statically fetched HTML and describes why it looks like
+// an unrendered client-side application. It returns an empty string for regular pages.
+func jsShellReason(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return ""
+	}
+
+	// Noscript warnings are checked before scripts are stripped
+	noscript := strings.ToLower(doc.Find("noscript").Text())
+	for _, phrase := range noscriptWarnings {
+		if strings.Contains(noscript, phrase) {
+			return fmt.Sprintf("noscript warning %q", phrase)
+		}
+	}
+
+	scripts := doc.Find("script").Length()
+	body := doc.Find("body")
+	body.Find("script, style, noscript, template").Remove()
+
+	for _, selector := range frameworkRootSelectors {
+		root := body.Find(selector).First()
+		if root.Length() > 0 && len(strings.TrimSpace(root.Text())) == 0 {
+			return fmt.Sprintf("empty framework root %s", selector)
+		}
+	}
+
+	text := strings.TrimSpace(body.Text())
+	if len(text) == 0 {
+		return "empty body"
+	}
+	if scripts > 0 && len(text) < minStaticTextLength {
+		return fmt.Sprintf("only %d characters of body text next to %d scripts", len(text), scripts)
+	}
+
+	return ""
+}
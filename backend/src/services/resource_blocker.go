@@ -0,0 +1,107 @@
+package services
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/chromedp/cdproto/network"
+)
+
+// resourceKinds maps blockable resource kinds to the CDP resource types they cover
+var resourceKinds = map[models.ResourceKind]network.ResourceType{
+	models.ResourceImage:      network.ResourceTypeImage,
+	models.ResourceMedia:      network.ResourceTypeMedia,
+	models.ResourceFont:       network.ResourceTypeFont,
+	models.ResourceStylesheet: network.ResourceTypeStylesheet,
+}
+
+// resourceBlocker decides which of a tab's requests are failed instead of loaded
+type resourceBlocker struct {
+	types    map[network.ResourceType]bool
+	patterns []*regexp.Regexp
+	domains  []string
+}
+
+// newResourceBlocker compiles block options. It returns nil when they block nothing.
+func newResourceBlocker(opts *models.BlockOptions) *resourceBlocker {
+	if opts == nil || opts.Empty() {
+		return nil
+	}
+	b := &resourceBlocker{types: make(map[network.ResourceType]bool)}
+	for _, kind := range opts.Resources {
+		if resourceType, ok := resourceKinds[kind]; ok {
+			b.types[resourceType] = true
+		}
+	}
+	for _, pattern := range opts.Patterns {
+		b.patterns = append(b.patterns, compileURLPattern(pattern))
+	}
+	for _, domain := range opts.Domains {
+		b.domains = append(b.domains, strings.ToLower(strings.TrimPrefix(domain, ".")))
+	}
+	return b
+}
+
+// compileURLPattern turns a pattern where * matches any run of characters
+// into a case-insensitive regexp matching whole URLs
+func compileURLPattern(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("(?i)^" + strings.Join(parts, ".*") + "$")
+}
+
+// blocks reports whether a request for rawURL of the given type is blocked.
+// Documents always load, so the page itself and its frames are never blocked.
+func (b *resourceBlocker) blocks(resourceType network.ResourceType, rawURL string) bool {
+	if b == nil || resourceType == network.ResourceTypeDocument {
+		return false
+	}
+	if b.types[resourceType] {
+		return true
+	}
+	for _, pattern := range b.patterns {
+		if pattern.MatchString(rawURL) {
+			return true
+		}
+	}
+	if len(b.domains) > 0 {
+		if u, err := url.Parse(rawURL); err == nil {
+			host := strings.ToLower(u.Hostname())
+			for _, domain := range b.domains {
+				if host == domain || strings.HasSuffix(host, "."+domain) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// defaultBlock returns the configured blocking for browser tabs, or nil when
+// nothing is blocked
+func (s *ScraperService) defaultBlock() *models.BlockOptions {
+	var opts models.BlockOptions
+	for _, kind := range s.config.BlockResources {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			opts.Resources = append(opts.Resources, models.ResourceKind(strings.ToLower(kind)))
+		}
+	}
+	for _, pattern := range s.config.BlockPatterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			opts.Patterns = append(opts.Patterns, pattern)
+		}
+	}
+	for _, domain := range s.config.BlockDomains {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			opts.Domains = append(opts.Domains, domain)
+		}
+	}
+	if opts.Empty() {
+		return nil
+	}
+	return &opts
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+)
+
+// revalidateTimeout bounds a conditional GET for a stale cache entry
+const revalidateTimeout = 10 * time.Second
+
+// ErrCacheMiss is returned when cache=only is requested and the page is not cached
+var ErrCacheMiss = errors.New("page is not in the response cache")
+
+// cacheValidators are the response headers used for conditional revalidation
+type cacheValidators struct {
+	etag         string
+	lastModified string
+}
+
+// pageFetch is the outcome of fetching (or re-serving) a single page
+type pageFetch struct {
+	html       string
+	finalURL   string // URL the page was served from after redirects, if known
+	mode       models.RenderMode
+	validators cacheValidators
+	cached     bool
+	age        time.Duration
+
+	screenshot    *models.Screenshot   // Captured in the browser, when requested
+	screenshotErr error                // Why a requested screenshot is missing
+	pdf           []byte               // Printed in the browser, when requested
+	pdfErr        error                // Why a requested PDF is missing
+	actionErr     error                // Why requested browser actions did not all run
+	waitErr       error                // Why the wait condition was not met before capture
+	blocked       map[string]int       // Requests the browser was told not to load, by resource type
+	har           *models.HAR          // Network traffic recorded in the browser, when requested
+	harErr        error                // Why a requested HAR is missing
+	apiResponses  []models.APIResponse // XHR and fetch responses captured in the browser, when requested
+	apiErr        error                // Why requested API responses are missing or incomplete
+	browserLog    *models.BrowserLog   // Console output, script errors and failed loads reported by the tab
+	fallback      bool                 // Fetched statically because the browser render the mode asked for failed
+}
+
+// cacheEntry is a fetched page kept in the response cache
+type cacheEntry struct {
+	html        string
+	finalURL    string
+	mode        models.RenderMode
+	validators  cacheValidators
+	fetchedAt   time.Time // When the content was last downloaded
+	validatedAt time.Time // When the content was last confirmed current
+}
+
+// ResponseCache keeps recently fetched pages keyed on normalized URL,
+// requested render mode and, for renders, the wait and block options, so
+// repeated scrapes skip the fetcher (and the browser) entirely while an entry
+// is fresh
+type ResponseCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// NewResponseCache creates a cache whose entries are fresh for ttl.
+// A ttl of zero disables caching; maxEntries <= 0 means unbounded.
+func NewResponseCache(ttl time.Duration, maxEntries int) *ResponseCache {
+	return &ResponseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*cacheEntry),
+	}
+}
+
+// enabled reports whether the cache stores anything at all
+func (c *ResponseCache) enabled() bool {
+	return c.ttl > 0
+}
+
+// get returns a copy of the entry for key and whether it is still fresh
+func (c *ResponseCache) get(key string) (cacheEntry, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return cacheEntry{}, false, false
+	}
+	return *entry, time.Since(entry.validatedAt) < c.ttl, true
+}
+
+// put stores a freshly fetched page, evicting the least recently validated
+// entry when the cache is full
+func (c *ResponseCache) put(key string, fetch *pageFetch) {
+	if !c.enabled() {
+		return
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, e := range c.entries {
+			if oldestKey == "" || e.validatedAt.Before(oldest) {
+				oldestKey, oldest = k, e.validatedAt
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+
+	c.entries[key] = &cacheEntry{
+		html:        fetch.html,
+		finalURL:    fetch.finalURL,
+		mode:        fetch.mode,
+		validators:  fetch.validators,
+		fetchedAt:   now,
+		validatedAt: now,
+	}
+}
+
+// touch marks the entry for key as confirmed current
+func (c *ResponseCache) touch(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		entry.validatedAt = time.Now()
+	}
+}
+
+// cacheKey identifies a page fetched with a requested render mode. Renders
+// also depend on how long the browser waited and what it blocked, so those
+// options are part of the key unless the page is only fetched statically.
+func cacheKey(rawURL string, opts models.ScrapeOptions) string {
+	key := string(opts.Mode) + " " + rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		key = string(opts.Mode) + " " + crawlKey(u)
+	}
+	if opts.Mode == models.RenderModeStatic {
+		return key
+	}
+	return key + " " + renderOptionsKey(opts.Wait, opts.Block)
+}
+
+// renderOptionsKey writes wait and block options in a canonical form, so
+// the same rules given in a different order share cache entries
+func renderOptionsKey(wait *models.WaitOptions, block *models.BlockOptions) string {
+	var b strings.Builder
+	if wait != nil {
+		fmt.Fprintf(&b, "wait=%s,%d,%d,%q,%q", wait.Strategy, wait.Ms, wait.TimeoutMs, wait.Selector, wait.Script)
+	}
+	if block != nil && !block.Empty() {
+		resources := make([]string, 0, len(block.Resources))
+		for _, kind := range block.Resources {
+			resources = append(resources, string(kind))
+		}
+		domains := make([]string, 0, len(block.Domains))
+		for _, domain := range block.Domains {
+			domains = append(domains, strings.TrimPrefix(domain, "."))
+		}
+		fmt.Fprintf(&b, " block=%q,%q,%q", canonicalList(resources), canonicalList(block.Patterns), canonicalList(domains))
+	}
+	return b.String()
+}
+
+// canonicalList lowercases, sorts and deduplicates items. Block rules match
+// case-insensitively, so case does not change what they block.
+func canonicalList(items []string) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, strings.ToLower(item))
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// servesFromCache reports whether item will be answered from the cache
+// without contacting the site, so the crawl can skip its politeness delay
+func (s *ScraperService) servesFromCache(run *scrapeRun, item crawlItem) bool {
+	switch {
+	case run.opts.Cache == models.CacheOnly:
+		return true
+	case run.opts.Cache == models.CacheBypass, s.browserTask(run, item).needsRender():
+		return false
+	}
+	_, fresh, _ := s.cache.get(cacheKey(item.url, run.opts))
+	return fresh
+}
+
+// fetchPageCached applies the cache policy around fetchPage
+func (s *ScraperService) fetchPageCached(ctx context.Context, run *scrapeRun, item crawlItem, userAgent string) (*pageFetch, error) {
+	key := cacheKey(item.url, run.opts)
+
+	switch run.opts.Cache {
+	case models.CacheOnly:
+		entry, _, ok := s.cache.get(key)
+		if !ok {
+			return nil, ErrCacheMiss
+		}
+		fetch := entry.serve()
+		if s.browserTask(run, item).needsRender() {
+			fetch.screenshotErr = errors.New("cache=only never renders pages")
+			fetch.actionErr = fetch.screenshotErr
+			fetch.harErr = fetch.screenshotErr
+			fetch.apiErr = fetch.screenshotErr
+		}
+		return fetch, nil
+
+	case models.CachePrefer:
+		// Screenshots, actions, HARs and API capture need a live render, so the cached copy will not do
+		if s.browserTask(run, item).needsRender() {
+			break
+		}
+		if entry, fresh, ok := s.cache.get(key); ok {
+			if fresh {
+				return entry.serve(), nil
+			}
+			if s.revalidate(ctx, item.url, userAgent, entry.validators) {
+				s.cache.touch(key)
+				return entry.serve(), nil
+			}
+		}
+	}
+
+	fetch, err := s.fetchPage(ctx, run, item, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	// A render changed by actions, made for a capture or cut short by an
+	// unmet wait is not the page as a plain scrape would see it, and a static
+	// fallback is not what the mode asked for
+	if !s.browserTask(run, item).needsRender() && fetch.actionErr == nil && fetch.waitErr == nil && !fetch.fallback {
+		s.cache.put(key, fetch)
+	}
+	return fetch, nil
+}
+
+// serve turns a cache entry into a page fetch
+func (e cacheEntry) serve() *pageFetch {
+	return &pageFetch{
+		html:       e.html,
+		finalURL:   e.finalURL,
+		mode:       e.mode,
+		validators: e.validators,
+		cached:     true,
+		age:        time.Since(e.fetchedAt),
+	}
+}
+
+// revalidate sends a conditional GET for a stale entry and reports whether
+// the site answered 304 Not Modified. Entries without validators, and any
+// other outcome, are refetched in full.
+func (s *ScraperService) revalidate(ctx context.Context, targetURL, userAgent string, validators cacheValidators) bool {
+	if validators.etag == "" && validators.lastModified == "" {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, revalidateTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if validators.etag != "" {
+		req.Header.Set("If-None-Match", validators.etag)
+	}
+	if validators.lastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.lastModified)
+	}
+
+	client := &http.Client{Transport: s.transport, CheckRedirect: s.guard.CheckRedirect}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("Revalidation failed for %s: %v", targetURL, err)
+		return false
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode == http.StatusNotModified
+}
+
+// validatorsFrom extracts cache validators from response headers
+func validatorsFrom(header http.Header) cacheValidators {
+	if header == nil {
+		return cacheValidators{}
+	}
+	return cacheValidators{
+		etag:         header.Get("ETag"),
+		lastModified: header.Get("Last-Modified"),
+	}
+}
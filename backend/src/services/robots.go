@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+)
+
+const (
+	// robotsFetchTimeout bounds a single robots.txt download
+	robotsFetchTimeout = 10 * time.Second
+	// robotsMaxBytes caps how much of a robots.txt file is read
+	robotsMaxBytes = 512 * 1024
+	// robotsErrorTTL is how long a failed robots.txt fetch or a 5xx response is cached before retrying
+	robotsErrorTTL = time.Minute
+)
+
+// RobotsDisallowedError is returned when robots.txt forbids fetching a URL
+type RobotsDisallowedError struct {
+	URL       string
+	UserAgent string
+}
+
+func (e *RobotsDisallowedError) Error() string {
+	return fmt.Sprintf("robots.txt disallows %s for user agent %q", e.URL, e.UserAgent)
+}
+
+// robotsEntry is a cached robots.txt for a single origin
+type robotsEntry struct {
+	data      *robotstxt.RobotsData
+	expiresAt time.Time
+}
+
+// RobotsChecker fetches, caches and evaluates robots.txt rules per origin
+type RobotsChecker struct {
+	client *http.Client
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*robotsEntry
+}
+
+// NewRobotsChecker creates a robots.txt checker that caches rules for ttl.
+// A nil client uses a default client with a short timeout.
+func NewRobotsChecker(ttl time.Duration, client *http.Client) *RobotsChecker {
+	if client == nil {
+		client = &http.Client{Timeout: robotsFetchTimeout}
+	}
+	return &RobotsChecker{
+		client: client,
+		ttl:    ttl,
+		cache:  make(map[string]*robotsEntry),
+	}
+}
+
+// Check evaluates target against its origin's robots.txt for userAgent.
+// It returns the Crawl-delay requested for that agent, or a *RobotsDisallowedError.
+func (r *RobotsChecker) Check(ctx context.Context, target *url.URL, userAgent string) (time.Duration, error) {
+	data, err := r.rules(ctx, target, userAgent)
+	if err != nil {
+		return 0, err
+	}
+
+	group := data.FindGroup(userAgent)
+	if !group.Test(target.RequestURI()) {
+		return 0, &RobotsDisallowedError{URL: target.String(), UserAgent: userAgent}
+	}
+
+	return group.CrawlDelay, nil
+}
+
+// rules returns the cached robots.txt for target's origin, fetching it when missing or expired
+func (r *RobotsChecker) rules(ctx context.Context, target *url.URL, userAgent string) (*robotstxt.RobotsData, error) {
+	origin := target.Scheme + "://" + target.Host
+
+	r.mu.Lock()
+	entry, ok := r.cache[origin]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.data, nil
+	}
+
+	data, status, err := r.fetch(ctx, origin, userAgent)
+	ttl := r.ttl
+	if status >= http.StatusInternalServerError {
+		// A server error disallows everything, but is usually temporary
+		ttl = min(ttl, robotsErrorTTL)
+	}
+	if err != nil && ctx.Err() != nil {
+		// The caller gave up; don't cache anything
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		// Treat an unreachable robots.txt as "allow all", but retry soon
+		log.Printf("robots.txt fetch failed for %s: %v", origin, err)
+		data, _ = robotstxt.FromStatusAndBytes(http.StatusNotFound, nil)
+		ttl = min(ttl, robotsErrorTTL)
+	}
+
+	r.mu.Lock()
+	r.cache[origin] = &robotsEntry{data: data, expiresAt: time.Now().Add(ttl)}
+	r.mu.Unlock()
+
+	return data, nil
+}
+
+// fetch downloads and parses robots.txt for origin, also returning the
+// response status (0 when no response arrived)
+func (r *RobotsChecker) fetch(ctx context.Context, origin, userAgent string) (*robotstxt.RobotsData, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, robotsMaxBytes))
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	// 4xx means "allow all", 5xx means "disallow all" (handled by robotstxt)
+	data, err := robotstxt.FromStatusAndBytes(resp.StatusCode, body)
+	return data, resp.StatusCode, err
+}
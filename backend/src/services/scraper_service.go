@@ -4,56 +4,190 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Michael-Obele/web-scraper-backend/src/config"
+	"github.com/Michael-Obele/web-scraper-backend/src/extract"
+	"github.com/Michael-Obele/web-scraper-backend/src/markdown"
 	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/storage"
 	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 	"github.com/gocolly/colly/v2"
 )
 
+// defaultUserAgent is used when no user agents are configured
+const defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
 // ScraperService handles web scraping operations
 type ScraperService struct {
-	config      *config.Config
-	chromedpCtx context.Context
-	cancel      context.CancelFunc
+	config     *config.Config
+	guard      *URLGuard
+	transport  *http.Transport
+	robots     *RobotsChecker
+	userAgents UserAgentRotator
+	cache      *ResponseCache
+	store      storage.ResultStore
+	browsers   *BrowserPool
+	proxy      *BrowserProxy // Guarded proxy for a launched browser; nil for a remote one
 }
 
-// NewScraperService creates a new scraper service and initializes a persistent Chromedp context
+// NewScraperService creates a new scraper service with a pool of Chromedp
+// tabs. The browser starts on first use.
 func NewScraperService(cfg *config.Config) *ScraperService {
+	// Every static request goes through the URL guard, including redirects and robots.txt
+	guard := NewURLGuard(cfg.AllowedCIDRs, cfg.DeniedCIDRs)
+	transport := guard.Transport()
+	robotsClient := &http.Client{
+		Timeout:       robotsFetchTimeout,
+		Transport:     transport,
+		CheckRedirect: guard.CheckRedirect,
+	}
+
 	opts := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.DisableGPU,
 		chromedp.NoDefaultBrowserCheck,
 		chromedp.Flag("disable-background-timer-throttling", false),
 		chromedp.Flag("disable-backgrounding-occluded-windows", false),
 		chromedp.Flag("disable-renderer-backgrounding", false),
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
+		chromedp.UserAgent(defaultUserAgent),
 	)
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	chromedpCtx, _ := chromedp.NewContext(allocCtx)
+
+	// A DevTools URL switches to a browser running elsewhere, such as a sidecar container
+	remote := cfg.ChromeWSURL != ""
+
+	// A launched browser connects through the guard too, loopback included,
+	// so WebSockets and rebinding hosts cannot get around the interceptor
+	var proxy *BrowserProxy
+	if !remote {
+		var err error
+		proxy, err = NewBrowserProxy(guard)
+		if err != nil {
+			log.Printf("Browser proxy unavailable, relying on request interception alone: %v", err)
+		} else {
+			opts = append(opts,
+				chromedp.ProxyServer(proxy.URL()),
+				chromedp.Flag("proxy-bypass-list", "<-loopback>"),
+			)
+		}
+	}
+
+	newAllocator := func() (context.Context, context.CancelFunc) {
+		return chromedp.NewExecAllocator(context.Background(), opts...)
+	}
+	if remote {
+		newAllocator = func() (context.Context, context.CancelFunc) {
+			return chromedp.NewRemoteAllocator(context.Background(), cfg.ChromeWSURL)
+		}
+	}
+
+	// Browser tabs are shared, capped and recovered by the pool
+	browsers := NewBrowserPool(newAllocator, remote, cfg.BrowserMaxTabs, cfg.GetBrowserQueueTimeout(),
+		cfg.BrowserRecycleAfter, cfg.GetBrowserHealthInterval())
+	if remote {
+		browsers.Connect()
+	}
 
 	return &ScraperService{
-		config:      cfg,
-		chromedpCtx: chromedpCtx,
-		cancel:      cancel,
+		config:     cfg,
+		guard:      guard,
+		transport:  transport,
+		robots:     NewRobotsChecker(cfg.GetRobotsCacheTTL(), robotsClient),
+		userAgents: NewUserAgentRotator(cfg.ScraperUAStrategy, cfg.ScraperUserAgents),
+		cache:      NewResponseCache(cfg.GetCacheTTL(), cfg.CacheMaxEntries),
+		browsers:   browsers,
+		proxy:      proxy,
 	}
 }
 
 // Close cleans up the scraper service resources
 func (s *ScraperService) Close() {
-	s.cancel()
+	s.browsers.Close()
+	if s.proxy != nil {
+		s.proxy.Close()
+	}
+}
+
+// BrowserStats reports the state of the browser tab pool
+func (s *ScraperService) BrowserStats() BrowserPoolStats {
+	return s.browsers.Stats()
+}
+
+// SetResultStore makes the service persist every successful scrape to store.
+// Call it before the service starts handling requests.
+func (s *ScraperService) SetResultStore(store storage.ResultStore) {
+	s.store = store
+}
+
+// Scrape performs a breadth-first crawl starting at the given URL, rendering pages per opts.Mode.
+// The top-level result fields describe the seed page; every visited page is listed in Pages.
+func (s *ScraperService) Scrape(ctx context.Context, targetURL string, opts models.ScrapeOptions) (*models.ScrapeResult, error) {
+	return s.ScrapeWithEvents(ctx, targetURL, opts, nil)
+}
+
+// ScrapeWithEvents behaves like Scrape and reports progress events to emit (which may be nil).
+// The final event is always EventDone or EventError.
+func (s *ScraperService) ScrapeWithEvents(ctx context.Context, targetURL string, opts models.ScrapeOptions, emit EventFunc) (*models.ScrapeResult, error) {
+	run := &scrapeRun{
+		result: &models.ScrapeResult{
+			Links:     []models.Link{},
+			Tables:    []models.Table{},
+			Pages:     []models.PageResult{},
+			Warnings:  []string{},
+			FetchedAt: time.Now(),
+		},
+		emit: emit,
+	}
+
+	result, err := s.scrape(ctx, targetURL, opts, run)
+	if err != nil {
+		run.event(models.ScrapeEvent{Type: models.EventError, URL: targetURL, Message: err.Error()})
+		return nil, err
+	}
+	s.saveResult(ctx, targetURL, result)
+
+	run.event(models.ScrapeEvent{Type: models.EventDone, URL: targetURL, Result: result})
+	return result, nil
 }
 
-// Scrape performs a web scrape of the given URL with the specified depth
-func (s *ScraperService) Scrape(ctx context.Context, targetURL string, depth int) (*models.ScrapeResult, error) {
-	result := &models.ScrapeResult{
-		Links:     []models.Link{},
-		Warnings:  []string{},
-		FetchedAt: time.Now(),
+// saveResult persists result and assigns its ID. A storage failure is
+// reported as a warning rather than failing a scrape that already succeeded.
+func (s *ScraperService) saveResult(ctx context.Context, targetURL string, result *models.ScrapeResult) {
+	if s.store == nil {
+		return
+	}
+
+	id, err := newRandomID()
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Result was not saved: %v", err))
+		return
+	}
+
+	result.ID = id
+	record := &models.StoredResult{
+		ID:          id,
+		URL:         targetURL,
+		FetchedAt:   result.FetchedAt,
+		ContentHash: storage.ContentHash(result),
+		Warnings:    result.Warnings,
+		Result:      result,
 	}
+	if err := s.store.Save(context.WithoutCancel(ctx), record); err != nil {
+		log.Printf("Failed to save result for %s: %v", targetURL, err)
+		result.ID = ""
+		result.Warnings = append(result.Warnings, "Result was not saved to history")
+	}
+}
+
+// scrape runs the crawl and fills in the top-level result fields
+func (s *ScraperService) scrape(ctx context.Context, targetURL string, opts models.ScrapeOptions, run *scrapeRun) (*models.ScrapeResult, error) {
+	result := run.result
 
 	// Parse and validate URL
 	parsedURL, err := url.Parse(targetURL)
@@ -61,111 +195,399 @@ func (s *ScraperService) Scrape(ctx context.Context, targetURL string, depth int
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
+	run.opts = s.normalizeOptions(opts, result)
+	if len(run.opts.Extract) > 0 {
+		run.schema, err = extract.CompileSchema(run.opts.Extract)
+		if err != nil {
+			return nil, fmt.Errorf("invalid extraction schema: %w", err)
+		}
+	}
+
 	// Create a context with timeout
 	timeoutCtx, cancel := context.WithTimeout(ctx, s.config.GetScraperTimeout())
 	defer cancel()
 
-	// Try to fetch with Chromedp for JS-rendered content first
-	html, err := s.fetchWithChromedp(timeoutCtx, targetURL)
+	rawHTML, err := s.crawl(timeoutCtx, parsedURL, run)
 	if err != nil {
-		log.Printf("Chromedp failed for %s: %v", targetURL, err)
-		// Fallback to Colly if Chromedp fails
-		result.Warnings = append(result.Warnings, fmt.Sprintf("Chromedp failed (%v), falling back to static fetch", err))
-		html, err = s.fetchWithColly(targetURL, depth, result)
-		if err != nil {
-			log.Printf("Colly also failed for %s: %v", targetURL, err)
-			return nil, fmt.Errorf("scraping failed: %w", err)
+		return nil, err
+	}
+
+	// The top-level fields mirror the seed page
+	seed := result.Pages[0]
+	result.Title = seed.Title
+	result.Markdown = seed.Markdown
+	result.Links = seed.Links
+	result.Tables = seed.Tables
+	result.Metadata = seed.Metadata
+	result.Extracted = seed.Extracted
+	// The seed page's browser log and API responses are reported once, on the result
+	result.BrowserLog, result.Pages[0].BrowserLog = seed.BrowserLog, nil
+	result.APIResponses, result.Pages[0].APIResponses = seed.APIResponses, nil
+
+	// Blocked requests add up across the crawl
+	for _, page := range result.Pages {
+		for resourceType, count := range page.Blocked {
+			if result.Blocked == nil {
+				result.Blocked = make(map[string]int)
+			}
+			result.Blocked[resourceType] += count
 		}
-	} else {
-		// Extract links from the Chromedp-rendered HTML
-		s.extractLinksFromHTML(html, parsedURL, result)
 	}
+	result.UserAgent = seed.UserAgent
+	result.Mode = seed.Mode
+	result.Cached = seed.Cached
+	result.Age = seed.Age
+	result.RawHTML = rawHTML
+
+	return result, nil
+}
+
+// normalizeOptions applies defaults and clamps the options to the configured limits
+func (s *ScraperService) normalizeOptions(opts models.ScrapeOptions, result *models.ScrapeResult) models.ScrapeOptions {
+	if opts.Depth < 1 {
+		opts.Depth = 1
+	}
+	if s.config.ScraperMaxDepth > 0 && opts.Depth > s.config.ScraperMaxDepth {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Depth %d exceeds the maximum of %d, clamping", opts.Depth, s.config.ScraperMaxDepth))
+		opts.Depth = s.config.ScraperMaxDepth
+	}
+
+	if opts.MaxPages < 1 || (s.config.ScraperMaxPages > 0 && opts.MaxPages > s.config.ScraperMaxPages) {
+		opts.MaxPages = s.config.ScraperMaxPages
+	}
+	if opts.MaxPages < 1 {
+		opts.MaxPages = 1
+	}
+
+	if opts.Mode == "" {
+		opts.Mode = models.RenderMode(s.config.DefaultRenderMode)
+	}
+	if !opts.Mode.Valid() {
+		opts.Mode = models.RenderModeAuto
+	}
+
+	if !opts.Cache.Valid() {
+		opts.Cache = models.CachePrefer
+	}
+
+	if opts.Content == "" {
+		opts.Content = models.ContentMode(s.config.DefaultContentMode)
+	}
+	if !opts.Content.Valid() {
+		opts.Content = models.ContentFull
+	}
+
+	if opts.Screenshot != nil {
+		screenshot := normalizeScreenshot(*opts.Screenshot)
+		opts.Screenshot = &screenshot
+	}
+
+	wait := s.defaultWait()
+	if opts.Wait != nil {
+		wait = normalizeWait(s.inferWaitStrategy(*opts.Wait), s.config.WaitTimeoutMs)
+	}
+	opts.Wait = &wait
+
+	if opts.Block == nil {
+		opts.Block = s.defaultBlock()
+	}
+
+	return opts
+}
+
+// scrapePage fetches a single page and converts it into a PageResult.
+// It returns the raw HTML alongside the page so the caller can keep it for the seed page.
+func (s *ScraperService) scrapePage(ctx context.Context, run *scrapeRun, item crawlItem, userAgent string) (*models.PageResult, string, error) {
+	pageURL, err := url.Parse(item.url)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	fetch, err := s.fetchPageCached(ctx, run, item, userAgent)
+	if err != nil {
+		return nil, "", err
+	}
+	html := fetch.html
 	if html == "" {
-		log.Printf("Warning: Empty HTML captured for %s", targetURL)
-		result.Warnings = append(result.Warnings, "Captured HTML was empty")
+		log.Printf("Warning: Empty HTML captured for %s", item.url)
+		// Point at what the browser saw go wrong, such as a blocked script or a crash
+		message := "Captured HTML was empty"
+		if summary := browserLogSummary(fetch.browserLog); summary != "" {
+			message += fmt.Sprintf(" (browser log: %s)", summary)
+		}
+		run.warn(item, message)
 	}
 
 	// Parse HTML and extract content
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	// Relative URLs resolve against where the page was actually served from
+	doc.Url = pageURL
+	if fetch.finalURL != "" && fetch.finalURL != item.url {
+		if finalURL, err := url.Parse(fetch.finalURL); err == nil {
+			doc.Url = finalURL
+		}
+	}
+
+	page := &models.PageResult{
+		URL:       item.url,
+		ParentURL: item.parentURL,
+		Depth:     item.depth,
+		Links:     s.extractLinks(doc),
+		UserAgent: userAgent,
+		Mode:      fetch.mode,
+		Cached:    fetch.cached,
+		Age:       int(fetch.age / time.Second),
+	}
+
+	if doc.Url != pageURL {
+		page.FinalURL = doc.Url.String()
+	}
+
+	metadata, err := extract.Metadata(doc)
+	if err != nil {
+		run.warn(item, "Metadata incomplete: "+err.Error())
+	}
+	page.Metadata = metadata
+
+	// Screenshots and HARs are taken of the seed page only and reported on the result
+	task := s.browserTask(run, item)
+	if task.screenshot != nil {
+		run.result.Screenshot = fetch.screenshot
+	}
+	run.captureWarning(item, fetch, "Screenshot failed", task.screenshot != nil, fetch.screenshotErr)
+
+	if task.har {
+		run.result.HAR = fetch.har
+	}
+	run.captureWarning(item, fetch, "HAR capture failed", task.har, fetch.harErr)
+
+	page.APIResponses = fetch.apiResponses
+	run.captureWarning(item, fetch, "API capture incomplete", task.captureAPI != nil, fetch.apiErr)
+
+	if fetch.waitErr != nil {
+		run.warn(item, fmt.Sprintf("Wait condition not met: %v", fetch.waitErr))
+	}
+	run.captureWarning(item, fetch, "Browser actions stopped", len(task.actions) > 0, fetch.actionErr)
+
+	page.Blocked = fetch.blocked
+	page.BrowserLog = fetch.browserLog
+	if run.schema != nil {
+		page.Extracted = run.schema.Extract(doc)
 	}
 
 	// Extract title
-	result.Title = strings.TrimSpace(doc.Find("title").First().Text())
-	if result.Title == "" {
-		result.Title = parsedURL.Host
+	page.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	if page.Title == "" {
+		page.Title = metadata.OpenGraph["og:title"]
+	}
+	if page.Title == "" {
+		page.Title = pageURL.Host
+	}
+	if fetch.har != nil {
+		fetch.har.Log.Pages[0].Title = page.Title
 	}
 
-	// Convert main content to markdown
-	result.Markdown = s.convertToMarkdown(doc)
-	result.RawHTML = html
+	// Convert the requested content to markdown
+	content := doc
+	if run.opts.Content == models.ContentMain {
+		main, ok := extract.MainContent(doc)
+		if ok {
+			content = main
+		} else {
+			run.warn(item, "No main content found, converting the full page")
+		}
+	}
+	page.Markdown = markdown.Convert(content)
+	page.Tables = markdown.Tables(content)
 
-	return result, nil
+	// A blank render is usually a script that crashed or never loaded
+	if html != "" && strings.TrimSpace(page.Markdown) == "" {
+		if summary := browserLogSummary(fetch.browserLog); summary != "" {
+			run.warn(item, fmt.Sprintf("Rendered page has no content (browser log: %s)", summary))
+		}
+	}
+
+	return page, html, nil
 }
 
 // fetchWithChromedp attempts to fetch content using the persistent headless Chrome instance
-func (s *ScraperService) fetchWithChromedp(ctx context.Context, targetURL string) (string, error) {
-	// Create a new tab from the persistent browser context
-	taskCtx, cancel := chromedp.NewContext(s.chromedpCtx)
-	defer cancel()
+func (s *ScraperService) fetchWithChromedp(ctx context.Context, targetURL, userAgent string, task browserTask) (*pageFetch, error) {
+	// Open a tab in the shared browser, queueing while all tabs are busy
+	taskCtx, release, err := s.browsers.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Close the tab early if the caller gives up
+	stop := context.AfterFunc(ctx, release)
+	defer stop()
 
-	// Apply timeout to the tab context
-	timeoutCtx, timeoutCancel := context.WithTimeout(taskCtx, s.config.GetChromedpTimeout())
+	wait := task.wait
+	if wait.Strategy == "" {
+		wait = s.defaultWait()
+	}
+
+	// Apply timeout to loading, waiting and actions, leaving room for the wait and
+	// any actions on top of the load. Listeners stay on the tab's own context, so
+	// requests made while capturing are still vetted and recorded after it runs out.
+	timeout := s.config.GetChromedpTimeout() + waitBudget(wait) + actionsBudget(task.actions)
+	timeoutCtx, timeoutCancel := context.WithTimeout(taskCtx, timeout)
 	defer timeoutCancel()
 
-	var html string
-	err := chromedp.Run(timeoutCtx,
+	// Vet every request the tab makes against the URL guard and the blocklist
+	interceptor := newBrowserInterceptor(s.guard, newResourceBlocker(task.block))
+	interceptor.listen(taskCtx)
+
+	// Keep the document's cache validators for later revalidation
+	var mu sync.Mutex
+	var validators *cacheValidators
+	chromedp.ListenTarget(taskCtx, func(ev any) {
+		if ev, ok := ev.(*network.EventResponseReceived); ok && ev.Type == network.ResourceTypeDocument {
+			mu.Lock()
+			defer mu.Unlock()
+			if validators == nil {
+				validators = &cacheValidators{
+					etag:         headerValue(ev.Response.Headers, "ETag"),
+					lastModified: headerValue(ev.Response.Headers, "Last-Modified"),
+				}
+			}
+		}
+	})
+
+	// Record the traffic from the first request when a HAR is asked for
+	var recorder *harRecorder
+	if task.har {
+		recorder = newHARRecorder()
+		recorder.listen(taskCtx)
+	}
+
+	// Collect console output, script errors and failed loads for diagnosis
+	logger := newBrowserLogger()
+	logger.listen(taskCtx)
+
+	// Watch for API responses from the first request
+	capture := newAPICapture(task.captureAPI)
+	if capture != nil {
+		capture.listen(taskCtx)
+	}
+
+	// Count requests from the start so network idle sees the whole load
+	var tracker *networkTracker
+	if wait.Strategy == models.WaitNetworkIdle {
+		tracker = newNetworkTracker()
+		tracker.listen(taskCtx)
+	}
+
+	actions := []chromedp.Action{
+		interceptor.enable(),
+		emulation.SetUserAgentOverride(userAgent),
+	}
+	if task.screenshot != nil {
+		actions = append(actions, chromedp.EmulateViewport(int64(task.screenshot.Width), int64(task.screenshot.Height)))
+	}
+
+	actions = append(actions,
 		chromedp.Navigate(targetURL),
 		chromedp.WaitReady("body"),
 		chromedp.WaitVisible("body", chromedp.ByQuery),
-		chromedp.Sleep(2*time.Second),
-		chromedp.OuterHTML("html", &html),
 	)
+	if err := chromedp.Run(timeoutCtx, actions...); err != nil {
+		if blockedErr := interceptor.blockedDocument(); blockedErr != nil {
+			return nil, blockedErr
+		}
+		return nil, &BrowserRenderError{Err: err, Log: logger.build()}
+	}
 
-	return html, err
+	// An unmet wait or a failed action leaves the page to be captured as it stands
+	fetch := &pageFetch{mode: models.RenderModeBrowser}
+	fetch.waitErr = waitForPage(timeoutCtx, wait, tracker)
+	if len(task.actions) > 0 {
+		fetch.actionErr = runBrowserActions(timeoutCtx, task.actions)
+	}
+
+	// Capture on a context of its own, so a wait or action that ran out the
+	// tab's time still leaves the page as it stands
+	captureCtx, captureCancel := context.WithTimeout(taskCtx, browserCaptureTimeout)
+	defer captureCancel()
+	if err := chromedp.Run(captureCtx,
+		chromedp.OuterHTML("html", &fetch.html),
+		chromedp.Location(&fetch.finalURL),
+	); err != nil {
+		return nil, &BrowserRenderError{Err: err, Log: logger.build()}
+	}
+	fetch.blocked = interceptor.blockedCounts()
+	fetch.browserLog = logger.build()
+	if recorder != nil {
+		fetch.har = recorder.build(fetch.finalURL)
+	}
+	if capture != nil {
+		collectCtx, collectCancel := context.WithTimeout(taskCtx, browserCaptureTimeout)
+		fetch.apiResponses, fetch.apiErr = capture.collect(collectCtx)
+		collectCancel()
+	}
+
+	mu.Lock()
+	if validators != nil {
+		fetch.validators = *validators
+	}
+	mu.Unlock()
+
+	// A failed capture leaves the rendered page usable. Each gets the load
+	// timeout of its own, since actions may have used up the tab's.
+	if task.screenshot != nil {
+		shotCtx, shotCancel := context.WithTimeout(taskCtx, s.config.GetChromedpTimeout())
+		fetch.screenshot, fetch.screenshotErr = captureScreenshot(shotCtx, *task.screenshot)
+		shotCancel()
+	}
+	if task.pdf != nil {
+		pdfCtx, pdfCancel := context.WithTimeout(taskCtx, s.config.GetChromedpTimeout())
+		fetch.pdf, fetch.pdfErr = printPDF(pdfCtx, *task.pdf)
+		pdfCancel()
+	}
+	return fetch, nil
+}
+
+// headerValue looks up a response header case-insensitively in CDP headers
+func headerValue(headers network.Headers, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			if s, ok := value.(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
 }
 
-// fetchWithColly fetches content using Colly (static crawling)
-func (s *ScraperService) fetchWithColly(targetURL string, depth int, result *models.ScrapeResult) (string, error) {
-	var html string
+// fetchWithColly fetches a single page using Colly (static crawling)
+func (s *ScraperService) fetchWithColly(ctx context.Context, targetURL, userAgent string) (*pageFetch, error) {
+	fetch := &pageFetch{mode: models.RenderModeStatic}
 	var fetchErr error
 
 	c := colly.NewCollector(
-		colly.MaxDepth(depth),
+		colly.StdlibContext(ctx),
 		colly.Async(false),
 	)
 
-	// Set user agent rotation
-	if len(s.config.ScraperUserAgents) > 0 {
-		c.UserAgent = s.config.ScraperUserAgents[0]
-	}
-
-	// Apply delay
-	c.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		Delay:       s.config.GetScraperDelay(),
-		RandomDelay: time.Second,
-	})
+	// Use the user agent picked by the rotator for this page
+	c.UserAgent = userAgent
 
-	// Extract links
-	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
-		link := e.Attr("href")
-		text := strings.TrimSpace(e.Text)
-
-		// Resolve relative URLs
-		absURL := e.Request.AbsoluteURL(link)
-		if absURL != "" {
-			result.Links = append(result.Links, models.Link{
-				Href: absURL,
-				Text: text,
-			})
-		}
-	})
+	// Re-check every connection and redirect hop against the URL guard
+	c.WithTransport(s.transport)
+	c.SetRedirectHandler(s.guard.CheckRedirect)
 
 	// Capture HTML
 	c.OnResponse(func(r *colly.Response) {
-		html = string(r.Body)
+		fetch.html = string(r.Body)
+		fetch.finalURL = r.Request.URL.String()
+		if r.Headers != nil {
+			fetch.validators = validatorsFrom(*r.Headers)
+		}
 	})
 
 	// Handle errors
@@ -175,22 +597,21 @@ func (s *ScraperService) fetchWithColly(targetURL string, depth int, result *mod
 
 	// Visit the URL
 	if err := c.Visit(targetURL); err != nil {
-		return "", fmt.Errorf("failed to visit URL: %w", err)
+		return nil, fmt.Errorf("failed to visit URL: %w", err)
 	}
 
 	if fetchErr != nil {
-		return "", fetchErr
+		return nil, fetchErr
 	}
 
-	return html, nil
+	return fetch, nil
 }
 
-// extractLinksFromHTML extracts links from HTML content
-func (s *ScraperService) extractLinksFromHTML(html string, baseURL *url.URL, result *models.ScrapeResult) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
-	if err != nil {
-		return
-	}
+// extractLinks extracts absolute links from a parsed document, resolving
+// them against its final URL and <base href>
+func (s *ScraperService) extractLinks(doc *goquery.Document) []models.Link {
+	links := []models.Link{}
+	baseURL := markdown.BaseURL(doc)
 
 	doc.Find("a[href]").Each(func(i int, sel *goquery.Selection) {
 		href, exists := sel.Attr("href")
@@ -204,131 +625,11 @@ func (s *ScraperService) extractLinksFromHTML(html string, baseURL *url.URL, res
 			return
 		}
 
-		result.Links = append(result.Links, models.Link{
+		links = append(links, models.Link{
 			Href: absURL.String(),
 			Text: strings.TrimSpace(sel.Text()),
 		})
 	})
-}
-
-// convertToMarkdown converts HTML content to markdown
-func (s *ScraperService) convertToMarkdown(doc *goquery.Document) string {
-	var markdown strings.Builder
-
-	// Process the entire body
-	doc.Find("body").Children().Each(func(i int, sel *goquery.Selection) {
-		s.nodeToMarkdown(sel, &markdown)
-	})
-
-	result := markdown.String()
-	if result == "" {
-		// Fallback to body text
-		result = strings.TrimSpace(doc.Find("body").Text())
-	}
-
-	return result
-}
-
-func (s *ScraperService) nodeToMarkdown(sel *goquery.Selection, markdown *strings.Builder) {
-	// Handle different node types
-	switch goquery.NodeName(sel) {
-	case "h1", "h2", "h3", "h4", "h5", "h6":
-		level := sel.Nodes[0].Data[1] - '0' // Extract heading level from tag name
-		text := strings.TrimSpace(sel.Text())
-		if text != "" {
-			markdown.WriteString(strings.Repeat("#", int(level)) + " " + text + "\n\n")
-		}
-	case "p":
-		text := strings.TrimSpace(sel.Text())
-		if text != "" {
-			markdown.WriteString(text + "\n\n")
-		}
-	case "ul", "ol":
-		sel.Find("li").Each(func(j int, li *goquery.Selection) {
-			text := strings.TrimSpace(li.Text())
-			if text != "" {
-				if goquery.NodeName(sel) == "ul" {
-					markdown.WriteString("- " + text + "\n")
-				} else {
-					markdown.WriteString(fmt.Sprintf("%d. %s\n", j+1, text))
-				}
-			}
-		})
-		markdown.WriteString("\n")
-	case "pre":
-		code := sel.Find("code")
-		lang := code.AttrOr("class", "")
-		lang = strings.TrimPrefix(lang, "language-")
-		markdown.WriteString(fmt.Sprintf("```%s\n%s\n```\n\n", lang, code.Text()))
-	case "code":
-		// Handle inline code if not in a pre block
-		if sel.Parent().Not("pre").Length() > 0 {
-			markdown.WriteString(fmt.Sprintf("`%s`", sel.Text()))
-		}
-	case "blockquote":
-		text := strings.TrimSpace(sel.Text())
-		lines := strings.Split(text, "\n")
-		for _, line := range lines {
-			markdown.WriteString("> " + line + "\n")
-		}
-		markdown.WriteString("\n")
-	case "img":
-		alt := sel.AttrOr("alt", "")
-		src := sel.AttrOr("src", "")
-		markdown.WriteString(fmt.Sprintf("![%s](%s)\n\n", alt, src))
-	case "a":
-		href := sel.AttrOr("href", "")
-		text := sel.Text()
-		markdown.WriteString(fmt.Sprintf("[%s](%s)", text, href))
-	default:
-		// For other block-level elements, just get the text
-		if isBlockElement(goquery.NodeName(sel)) {
-			text := strings.TrimSpace(sel.Text())
-			if text != "" {
-				markdown.WriteString(text + "\n\n")
-			}
-		} else { // Inline elements
-			markdown.WriteString(sel.Text())
-		}
-	}
-}
 
-func isBlockElement(tag string) bool {
-	blockTags := map[string]bool{
-		"address":    true,
-		"article":    true,
-		"aside":      true,
-		"blockquote": true,
-		"canvas":     true,
-		"dd":         true,
-		"div":        true,
-		"dl":         true,
-		"dt":         true,
-		"fieldset":   true,
-		"figcaption": true,
-		"figure":     true,
-		"footer":     true,
-		"form":       true,
-		"h1":         true,
-		"h2":         true,
-		"h3":         true,
-		"h4":         true,
-		"h5":         true,
-		"h6":         true,
-		"header":     true,
-		"hr":         true,
-		"li":         true,
-		"main":       true,
-		"nav":        true,
-		"noscript":   true,
-		"ol":         true,
-		"p":          true,
-		"pre":        true,
-		"section":    true,
-		"table":      true,
-		"tfoot":      true,
-		"ul":         true,
-		"video":      true,
-	}
-	return blockTags[tag]
+	return links
 }
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	defaultViewportWidth  = 1280
+	defaultViewportHeight = 800
+	defaultJPEGQuality    = 80
+	// maxCaptureHeight keeps full-page captures within what Chrome can encode
+	maxCaptureHeight = 16384
+)
+
+// elementRect is the document position of an element, as reported by elementRectJS
+type elementRect struct {
+	Found  bool    `json:"found"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// elementRectJS finds the first element matching a selector (given as a JSON
+// string) and returns its bounding box relative to the document
+const elementRectJS = `(() => {
+	const el = document.querySelector(%s);
+	if (!el) return {found: false};
+	el.scrollIntoView({block: "center"});
+	const r = el.getBoundingClientRect();
+	return {found: true, x: r.left + window.scrollX, y: r.top + window.scrollY, width: r.width, height: r.height};
+})()`
+
+// Screenshot renders targetURL in the browser and captures it as opts asks.
+// The URL guard and robots.txt apply as for a scrape; the page is not
+// converted, cached or saved to history.
+func (s *ScraperService) Screenshot(ctx context.Context, targetURL string, opts models.ScreenshotOptions) (*models.Screenshot, error) {
+	opts = normalizeScreenshot(opts)
+	fetch, err := s.renderForCapture(ctx, targetURL, browserTask{screenshot: &opts})
+	if err != nil {
+		return nil, err
+	}
+	if fetch.screenshot == nil {
+		return nil, fmt.Errorf("screenshot failed: %w", fetch.screenshotErr)
+	}
+	return fetch.screenshot, nil
+}
+
+// normalizeScreenshot applies screenshot defaults
+func normalizeScreenshot(opts models.ScreenshotOptions) models.ScreenshotOptions {
+	if opts.Format == "" {
+		opts.Format = models.ImagePNG
+	}
+	if opts.Format == models.ImageJPEG && opts.Quality == 0 {
+		opts.Quality = defaultJPEGQuality
+	}
+	if opts.Width == 0 {
+		opts.Width = defaultViewportWidth
+	}
+	if opts.Height == 0 {
+		opts.Height = defaultViewportHeight
+	}
+	return opts
+}
+
+// captureScreenshot captures the current page in the tab behind ctx: the
+// viewport, the whole page or a single element
+func captureScreenshot(ctx context.Context, opts models.ScreenshotOptions) (*models.Screenshot, error) {
+	shot := &models.Screenshot{Format: opts.Format, Width: opts.Width, Height: opts.Height}
+
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var clip *page.Viewport
+		switch {
+		case opts.Selector != "":
+			selector, _ := json.Marshal(opts.Selector)
+			var rect elementRect
+			if err := chromedp.Evaluate(fmt.Sprintf(elementRectJS, selector), &rect).Do(ctx); err != nil {
+				return err
+			}
+			if !rect.Found {
+				return fmt.Errorf("no element matches %q", opts.Selector)
+			}
+			if rect.Width == 0 || rect.Height == 0 {
+				return fmt.Errorf("element %q has no size", opts.Selector)
+			}
+			clip = &page.Viewport{X: math.Floor(rect.X), Y: math.Floor(rect.Y), Width: math.Ceil(rect.Width), Height: math.Ceil(rect.Height), Scale: 1}
+		case opts.FullPage:
+			_, _, _, _, _, content, err := page.GetLayoutMetrics().Do(ctx)
+			if err != nil {
+				return err
+			}
+			clip = &page.Viewport{Width: math.Ceil(content.Width), Height: math.Min(math.Ceil(content.Height), maxCaptureHeight), Scale: 1}
+		}
+
+		capture := page.CaptureScreenshot().WithFromSurface(true)
+		if clip != nil {
+			capture = capture.WithClip(clip).WithCaptureBeyondViewport(true)
+			shot.Width, shot.Height = int(clip.Width), int(clip.Height)
+		}
+		if opts.Format == models.ImageJPEG {
+			capture = capture.WithFormat(page.CaptureScreenshotFormatJpeg).WithQuality(int64(opts.Quality))
+		} else {
+			capture = capture.WithFormat(page.CaptureScreenshotFormatPng)
+		}
+
+		data, err := capture.Do(ctx)
+		if err != nil {
+			return err
+		}
+		shot.Data = data
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if len(shot.Data) == 0 {
+		return nil, errors.New("browser returned an empty image")
+	}
+	return shot, nil
+}
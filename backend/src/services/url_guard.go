@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxRedirects is the number of redirect hops the static fetcher follows
+const maxRedirects = 10
+
+// defaultBlockedPrefixes are networks a public scraper must never reach:
+// loopback, private, link-local (including cloud metadata), CGNAT and reserved ranges.
+var defaultBlockedPrefixes = []string{
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.0.2.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"198.51.100.0/24",
+	"203.0.113.0/24",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"64:ff9b::/96",
+	"100::/64",
+	"2001:db8::/32",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+}
+
+// BlockedTargetError is returned when a URL points at a forbidden network
+type BlockedTargetError struct {
+	URL    string
+	IP     string
+	Reason string
+}
+
+func (e *BlockedTargetError) Error() string {
+	if e.IP != "" {
+		return fmt.Sprintf("target %s is blocked: %s (%s)", e.URL, e.Reason, e.IP)
+	}
+	return fmt.Sprintf("target %s is blocked: %s", e.URL, e.Reason)
+}
+
+// URLGuard rejects URLs that resolve to private, loopback, link-local or metadata addresses
+type URLGuard struct {
+	reserved []netip.Prefix
+	allow    []netip.Prefix
+	deny     []netip.Prefix
+	resolver *net.Resolver
+}
+
+// NewURLGuard creates a guard from allow and deny CIDR lists.
+// Explicit denies win over explicit allows, which win over the built-in blocklist.
+// Bare IP addresses are accepted as single-host prefixes; invalid entries are logged and ignored.
+func NewURLGuard(allowCIDRs, denyCIDRs []string) *URLGuard {
+	return &URLGuard{
+		reserved: parsePrefixes(defaultBlockedPrefixes),
+		allow:    parsePrefixes(allowCIDRs),
+		deny:     parsePrefixes(denyCIDRs),
+		resolver: net.DefaultResolver,
+	}
+}
+
+// CheckURL resolves the URL's host and returns a *BlockedTargetError if any
+// of its addresses is forbidden. Non-HTTP(S) schemes are rejected as well.
+func (g *URLGuard) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return &BlockedTargetError{URL: rawURL, Reason: fmt.Sprintf("scheme %q is not allowed", u.Scheme)}
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return &BlockedTargetError{URL: rawURL, Reason: "missing host"}
+	}
+
+	var addrs []netip.Addr
+	if ip, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{ip}
+	} else {
+		addrs, err = g.resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", host, err)
+		}
+	}
+
+	for _, ip := range addrs {
+		if reason := g.blockReason(ip); reason != "" {
+			return &BlockedTargetError{URL: rawURL, IP: ip.String(), Reason: reason}
+		}
+	}
+
+	return nil
+}
+
+// CheckRedirect is an http.Client CheckRedirect hook that re-checks every hop
+func (g *URLGuard) CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return g.CheckURL(req.Context(), req.URL.String())
+}
+
+// Transport returns an HTTP transport whose connections are checked against the
+// guard at dial time, so DNS rebinding cannot sneak past CheckURL
+func (g *URLGuard) Transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = g.dialer().DialContext
+	return transport
+}
+
+// dialer returns a dialer that refuses connections to addresses the guard forbids
+func (g *URLGuard) dialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if reason := g.blockReason(ip); reason != "" {
+				return &BlockedTargetError{URL: address, IP: ip.String(), Reason: reason}
+			}
+			return nil
+		},
+	}
+}
+
+// blockReason describes why ip is forbidden, or returns an empty string if it is allowed
+func (g *URLGuard) blockReason(ip netip.Addr) string {
+	ip = ip.Unmap()
+	for _, prefix := range g.deny {
+		if prefix.Contains(ip) {
+			return fmt.Sprintf("address is in denied range %s", prefix)
+		}
+	}
+	for _, prefix := range g.allow {
+		if prefix.Contains(ip) {
+			return ""
+		}
+	}
+	for _, prefix := range g.reserved {
+		if prefix.Contains(ip) {
+			return fmt.Sprintf("address is in reserved range %s", prefix)
+		}
+	}
+	return ""
+}
+
+// isBlockedTarget reports whether err was caused by the URL guard
+func isBlockedTarget(err error) bool {
+	var blocked *BlockedTargetError
+	return errors.As(err, &blocked)
+}
+
+// parsePrefixes parses CIDR strings and bare IPs, skipping invalid entries
+func parsePrefixes(values []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		if ip, err := netip.ParseAddr(value); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+			continue
+		}
+		log.Printf("Ignoring invalid CIDR %q", value)
+	}
+	return prefixes
+}
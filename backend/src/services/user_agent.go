@@ -0,0 +1,79 @@
+package services
+
+import (
+	"hash/maphash"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+// User agent rotation strategies accepted in SCRAPER_UA_STRATEGY
+const (
+	UAStrategyRoundRobin = "round-robin"
+	UAStrategyRandom     = "random"
+	UAStrategySticky     = "sticky"
+)
+
+// UserAgentRotator picks the user agent presented for a request to host
+type UserAgentRotator interface {
+	Next(host string) string
+}
+
+// NewUserAgentRotator builds the rotator for strategy, defaulting to round-robin
+func NewUserAgentRotator(strategy string, agents []string) UserAgentRotator {
+	pool := make([]string, 0, len(agents))
+	for _, agent := range agents {
+		if agent = strings.TrimSpace(agent); agent != "" {
+			pool = append(pool, agent)
+		}
+	}
+	if len(pool) == 0 {
+		pool = []string{defaultUserAgent}
+	}
+
+	switch strings.ToLower(strategy) {
+	case UAStrategyRandom:
+		return &randomRotator{agents: pool}
+	case UAStrategySticky:
+		return &stickyRotator{agents: pool, seed: maphash.MakeSeed()}
+	default:
+		return &roundRobinRotator{agents: pool}
+	}
+}
+
+// roundRobinRotator cycles through the agents in order
+type roundRobinRotator struct {
+	mu     sync.Mutex
+	agents []string
+	next   int
+}
+
+func (r *roundRobinRotator) Next(host string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	agent := r.agents[r.next]
+	r.next = (r.next + 1) % len(r.agents)
+	return agent
+}
+
+// randomRotator picks a uniformly random agent for every request
+type randomRotator struct {
+	agents []string
+}
+
+func (r *randomRotator) Next(host string) string {
+	return r.agents[rand.Intn(len(r.agents))]
+}
+
+// stickyRotator derives each host's agent from a hash of the host, so a host
+// keeps its agent without the rotator remembering every host it has seen.
+// The seed spreads hosts differently in each process.
+type stickyRotator struct {
+	agents []string
+	seed   maphash.Seed
+}
+
+func (r *stickyRotator) Next(host string) string {
+	sum := maphash.String(r.seed, strings.ToLower(host))
+	return r.agents[sum%uint64(len(r.agents))]
+}
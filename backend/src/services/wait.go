@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// defaultQuietMs is how long the network or DOM must stay quiet by default
+	defaultQuietMs = 500
+	// defaultDelayMs matches the fixed pause every render used to take
+	defaultDelayMs = 2000
+	// idlePollInterval is how often the network idle wait checks for quiet
+	idlePollInterval = 50 * time.Millisecond
+)
+
+// domStableJS resolves true once the document goes %[1]d ms without a
+// mutation, or false after %[2]d ms
+const domStableJS = `new Promise(resolve => {
+	let quiet;
+	const done = settled => {
+		observer.disconnect();
+		clearTimeout(quiet);
+		clearTimeout(limit);
+		resolve(settled);
+	};
+	const observer = new MutationObserver(() => {
+		clearTimeout(quiet);
+		quiet = setTimeout(() => done(true), %[1]d);
+	});
+	observer.observe(document, {childList: true, subtree: true, attributes: true, characterData: true});
+	quiet = setTimeout(() => done(true), %[1]d);
+	const limit = setTimeout(() => done(false), %[2]d);
+})`
+
+// defaultWait returns the configured wait for pages rendered in the browser.
+// Strategies that need a selector or script cannot be defaults, so they fall
+// back to a fixed delay.
+func (s *ScraperService) defaultWait() models.WaitOptions {
+	wait := models.WaitOptions{
+		Strategy:  models.WaitStrategy(s.config.DefaultWaitStrategy),
+		Ms:        s.config.DefaultWaitMs,
+		TimeoutMs: s.config.WaitTimeoutMs,
+	}
+	switch wait.Strategy {
+	case models.WaitNetworkIdle, models.WaitDOMStable, models.WaitDelay:
+	default:
+		wait.Strategy = models.WaitDelay
+	}
+	return normalizeWait(wait, s.config.WaitTimeoutMs)
+}
+
+// inferWaitStrategy picks a strategy for a wait given without one: a selector
+// waits for it, a script polls it, and anything else keeps the configured
+// strategy with the requested durations
+func (s *ScraperService) inferWaitStrategy(wait models.WaitOptions) models.WaitOptions {
+	switch {
+	case wait.Strategy != "":
+	case wait.Selector != "":
+		wait.Strategy = models.WaitSelector
+	case wait.Script != "":
+		wait.Strategy = models.WaitFunction
+	default:
+		wait.Strategy = s.defaultWait().Strategy
+	}
+	return wait
+}
+
+// normalizeWait fills in the quiet period or delay and the timeout
+func normalizeWait(wait models.WaitOptions, timeoutMs int) models.WaitOptions {
+	if wait.Ms <= 0 {
+		wait.Ms = defaultQuietMs
+		if wait.Strategy == models.WaitDelay {
+			wait.Ms = defaultDelayMs
+		}
+	}
+	if wait.TimeoutMs <= 0 {
+		wait.TimeoutMs = timeoutMs
+	}
+	if wait.TimeoutMs <= 0 {
+		wait.TimeoutMs = int(defaultActionTimeout / time.Millisecond)
+	}
+	return wait
+}
+
+// networkTracker counts a tab's requests in flight, for the network idle wait
+type networkTracker struct {
+	mu       sync.Mutex
+	inFlight map[network.RequestID]struct{}
+	last     time.Time
+}
+
+// newNetworkTracker creates a tracker for a single tab
+func newNetworkTracker() *networkTracker {
+	return &networkTracker{inFlight: make(map[network.RequestID]struct{}), last: time.Now()}
+}
+
+// listen attaches the tracker to the tab in ctx. Call it before the first Run.
+func (t *networkTracker) listen(ctx context.Context) {
+	chromedp.ListenTarget(ctx, func(ev any) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		switch ev := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			t.inFlight[ev.RequestID] = struct{}{}
+		case *network.EventLoadingFinished:
+			delete(t.inFlight, ev.RequestID)
+		case *network.EventLoadingFailed:
+			delete(t.inFlight, ev.RequestID)
+		default:
+			return
+		}
+		t.last = time.Now()
+	})
+}
+
+// idleFor reports whether no request has been in flight for at least quiet
+func (t *networkTracker) idleFor(quiet time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.inFlight) == 0 && time.Since(t.last) >= quiet
+}
+
+// waitBudget is the most time waitForPage can take
+func waitBudget(wait models.WaitOptions) time.Duration {
+	if wait.Strategy == models.WaitDelay {
+		return time.Duration(wait.Ms) * time.Millisecond
+	}
+	return time.Duration(wait.TimeoutMs) * time.Millisecond
+}
+
+// waitForPage waits in the tab behind ctx as wait asks. tracker must have been
+// listening since before navigation when the strategy is network idle.
+func waitForPage(ctx context.Context, wait models.WaitOptions, tracker *networkTracker) error {
+	quiet := time.Duration(wait.Ms) * time.Millisecond
+	timeout := time.Duration(wait.TimeoutMs) * time.Millisecond
+	if wait.Strategy == models.WaitDelay {
+		return sleepContext(ctx, quiet)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var err error
+	switch wait.Strategy {
+	case models.WaitNetworkIdle:
+		for !tracker.idleFor(quiet) {
+			if err = sleepContext(waitCtx, idlePollInterval); err != nil {
+				break
+			}
+		}
+
+	case models.WaitDOMStable:
+		var settled bool
+		err = chromedp.Run(waitCtx, chromedp.Evaluate(fmt.Sprintf(domStableJS, wait.Ms, wait.TimeoutMs), &settled, awaitPromise))
+		if err == nil && !settled {
+			err = context.DeadlineExceeded
+		}
+
+	case models.WaitSelector:
+		err = chromedp.Run(waitCtx, chromedp.WaitVisible(wait.Selector, chromedp.ByQuery))
+
+	case models.WaitFunction:
+		err = chromedp.Run(waitCtx, chromedp.Poll(wait.Script, nil,
+			chromedp.WithPollingInterval(idlePollInterval),
+			chromedp.WithPollingTimeout(timeout),
+		))
+
+	default:
+		return fmt.Errorf("unknown wait strategy %q", wait.Strategy)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, chromedp.ErrPollingTimeout) {
+		if ctx.Err() == nil {
+			return fmt.Errorf("%s not reached within %d ms", wait.Strategy, wait.TimeoutMs)
+		}
+	}
+	return err
+}
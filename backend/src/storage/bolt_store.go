@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	resultsBucket   = []byte("results")
+	summariesBucket = []byte("summaries")
+	harsBucket      = []byte("hars")
+	byURLBucket     = []byte("by_url")
+	byTimeBucket    = []byte("by_time")
+)
+
+// BoltStore is a ResultStore backed by an embedded bbolt database file.
+// Results are stored as JSON by ID, with their history summaries and HARs
+// in buckets of their own. Secondary indexes of normalized URL + fetch time
+// and of fetch time alone let history be read newest first and the oldest
+// results be removed once the store is full.
+type BoltStore struct {
+	db         *bolt.DB
+	maxEntries int
+}
+
+// NewBoltStore opens (or creates) the database at path, holding at most
+// maxEntries results (0 for no limit)
+func NewBoltStore(path string, maxEntries int) (*BoltStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open result store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{resultsBucket, summariesBucket, harsBucket, byURLBucket, byTimeBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise result store: %w", err)
+	}
+
+	return &BoltStore{db: db, maxEntries: maxEntries}, nil
+}
+
+// Save stores record and indexes it under its URL
+func (s *BoltStore) Save(ctx context.Context, record *models.StoredResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	stored, har := splitPayloads(record)
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	summary, err := json.Marshal(stored.Summary())
+	if err != nil {
+		return fmt.Errorf("failed to encode result summary: %w", err)
+	}
+	var harData []byte
+	if har != nil {
+		if harData, err = json.Marshal(har); err != nil {
+			return fmt.Errorf("failed to encode HAR: %w", err)
+		}
+	}
+
+	id := []byte(record.ID)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		// Drop the index entries of an earlier save under this ID
+		if err := removeResult(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Bucket(resultsBucket).Put(id, data); err != nil {
+			return err
+		}
+		if err := tx.Bucket(summariesBucket).Put(id, summary); err != nil {
+			return err
+		}
+		if harData != nil {
+			if err := tx.Bucket(harsBucket).Put(id, harData); err != nil {
+				return err
+			}
+		}
+		if err := tx.Bucket(byURLBucket).Put(historyKey(record.URL, record.FetchedAt, record.ID), id); err != nil {
+			return err
+		}
+		if err := tx.Bucket(byTimeBucket).Put(timeKey(record.FetchedAt, record.ID), id); err != nil {
+			return err
+		}
+		return s.prune(tx)
+	})
+}
+
+// prune removes the oldest results past s.maxEntries
+func (s *BoltStore) prune(tx *bolt.Tx) error {
+	if s.maxEntries <= 0 {
+		return nil
+	}
+	var stale [][]byte
+	cursor := tx.Bucket(byTimeBucket).Cursor()
+	kept := 0
+	for k, v := cursor.Last(); k != nil; k, v = cursor.Prev() {
+		if kept < s.maxEntries {
+			kept++
+			continue
+		}
+		stale = append(stale, bytes.Clone(v))
+	}
+	for _, id := range stale {
+		if err := removeResult(tx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeResult deletes a result, its summary, HAR and index entries, if it exists
+func removeResult(tx *bolt.Tx, id []byte) error {
+	data := tx.Bucket(summariesBucket).Get(id)
+	if data == nil {
+		return nil
+	}
+	var summary models.HistoryEntry
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return fmt.Errorf("failed to decode result summary %s: %w", id, err)
+	}
+
+	deletes := []struct {
+		bucket, key []byte
+	}{
+		{resultsBucket, id},
+		{summariesBucket, id},
+		{harsBucket, id},
+		{byURLBucket, historyKey(summary.URL, summary.FetchedAt, summary.ID)},
+		{byTimeBucket, timeKey(summary.FetchedAt, summary.ID)},
+	}
+	for _, del := range deletes {
+		if err := tx.Bucket(del.bucket).Delete(del.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the stored result with the given ID
+func (s *BoltStore) Get(ctx context.Context, id string) (*models.StoredResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var record models.StoredResult
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(resultsBucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &record)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// HAR returns the HAR saved with the result with the given ID
+func (s *BoltStore) HAR(ctx context.Context, id string) (*models.HAR, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var har *models.HAR
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(summariesBucket).Get([]byte(id)) == nil {
+			return ErrNotFound
+		}
+		data := tx.Bucket(harsBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		har = &models.HAR{}
+		return json.Unmarshal(data, har)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return har, nil
+}
+
+// History lists results for rawURL, newest first
+func (s *BoltStore) History(ctx context.Context, rawURL string, limit int) ([]models.HistoryEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	prefix := urlPrefix(normalizeURL(rawURL))
+	entries := []models.HistoryEntry{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		summaries := tx.Bucket(summariesBucket)
+		cursor := tx.Bucket(byURLBucket).Cursor()
+
+		// Seek past the last key with this prefix, then walk backwards
+		upper := append(bytes.Clone(prefix), 0xff)
+		k, v := cursor.Seek(upper)
+		if k == nil {
+			k, v = cursor.Last()
+		} else {
+			k, v = cursor.Prev()
+		}
+
+		for ; k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Prev() {
+			if limit > 0 && len(entries) >= limit {
+				break
+			}
+			data := summaries.Get(v)
+			if data == nil {
+				continue
+			}
+			var entry models.HistoryEntry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return fmt.Errorf("failed to decode result summary %s: %w", v, err)
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Close closes the database file
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// urlPrefix is the index prefix for a normalized URL. The NUL separator
+// keeps "https://a.com/x" from matching "https://a.com/xy".
+func urlPrefix(normalized string) []byte {
+	return append([]byte(normalized), 0)
+}
+
+// historyKey orders index entries by fetch time within a URL
+func historyKey(rawURL string, fetchedAt time.Time, id string) []byte {
+	return append(urlPrefix(normalizeURL(rawURL)), timeKey(fetchedAt, id)...)
+}
+
+// timeKey orders index entries by fetch time
+func timeKey(fetchedAt time.Time, id string) []byte {
+	key := binary.BigEndian.AppendUint64(nil, uint64(fetchedAt.UnixNano()))
+	return append(key, id...)
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+)
+
+// MemoryStore is a ResultStore that keeps results in process memory.
+// It is used when no database path is configured; results do not survive a restart.
+type MemoryStore struct {
+	maxEntries int
+
+	mu        sync.RWMutex
+	results   map[string]*models.StoredResult
+	summaries map[string]models.HistoryEntry
+	hars      map[string]*models.HAR
+	byURL     map[string][]string
+	order     []string // IDs in the order they were saved, oldest first
+}
+
+// NewMemoryStore creates an empty in-memory store holding at most maxEntries
+// results (0 for no limit)
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		results:    make(map[string]*models.StoredResult),
+		summaries:  make(map[string]models.HistoryEntry),
+		hars:       make(map[string]*models.HAR),
+		byURL:      make(map[string][]string),
+	}
+}
+
+// Save stores record and indexes it under its URL
+func (s *MemoryStore) Save(ctx context.Context, record *models.StoredResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	stored, har := splitPayloads(record)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.results[record.ID]; !exists {
+		key := normalizeURL(record.URL)
+		s.byURL[key] = append(s.byURL[key], record.ID)
+		s.order = append(s.order, record.ID)
+	}
+	s.results[record.ID] = stored
+	s.summaries[record.ID] = stored.Summary()
+	if har != nil {
+		s.hars[record.ID] = har
+	} else {
+		delete(s.hars, record.ID)
+	}
+
+	for s.maxEntries > 0 && len(s.order) > s.maxEntries {
+		s.remove(s.order[0])
+		s.order = s.order[1:]
+	}
+	return nil
+}
+
+// remove deletes a result and its index entries. Call with s.mu held.
+func (s *MemoryStore) remove(id string) {
+	record := s.results[id]
+	if record == nil {
+		return
+	}
+	key := normalizeURL(record.URL)
+	s.byURL[key] = slices.DeleteFunc(s.byURL[key], func(other string) bool { return other == id })
+	if len(s.byURL[key]) == 0 {
+		delete(s.byURL, key)
+	}
+	delete(s.results, id)
+	delete(s.summaries, id)
+	delete(s.hars, id)
+}
+
+// Get returns the stored result with the given ID
+func (s *MemoryStore) Get(ctx context.Context, id string) (*models.StoredResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.results[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return record, nil
+}
+
+// HAR returns the HAR saved with the result with the given ID
+func (s *MemoryStore) HAR(ctx context.Context, id string) (*models.HAR, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.results[id]; !ok {
+		return nil, ErrNotFound
+	}
+	return s.hars[id], nil
+}
+
+// History lists results for rawURL, newest first
+func (s *MemoryStore) History(ctx context.Context, rawURL string, limit int) ([]models.HistoryEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := []models.HistoryEntry{}
+	for _, id := range s.byURL[normalizeURL(rawURL)] {
+		entries = append(entries, s.summaries[id])
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].FetchedAt.After(entries[j].FetchedAt)
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+)
+
+// ErrNotFound is returned when a stored result does not exist
+var ErrNotFound = errors.New("result not found")
+
+// ResultStore persists scrape results so they can be retrieved without re-scraping
+type ResultStore interface {
+	// Save stores a result without its screenshot, keeping its HAR apart.
+	// The record's ID must already be set. Once the store holds its maximum
+	// number of results, the oldest are removed.
+	Save(ctx context.Context, record *models.StoredResult) error
+	// Get returns the stored result with the given ID, or ErrNotFound
+	Get(ctx context.Context, id string) (*models.StoredResult, error)
+	// HAR returns the HAR saved with a result: nil when none was recorded,
+	// or ErrNotFound when the result does not exist
+	HAR(ctx context.Context, id string) (*models.HAR, error)
+	// History lists results for a URL, newest first, up to limit entries
+	History(ctx context.Context, rawURL string, limit int) ([]models.HistoryEntry, error)
+	// Close releases the store's resources
+	Close() error
+}
+
+// ContentHash returns the SHA-256 of the Markdown of every page in result
+func ContentHash(result *models.ScrapeResult) string {
+	h := sha256.New()
+	if len(result.Pages) == 0 {
+		h.Write([]byte(result.Markdown))
+	}
+	for _, page := range result.Pages {
+		h.Write([]byte(page.URL))
+		h.Write([]byte{0})
+		h.Write([]byte(page.Markdown))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeURL gives equivalent URLs the same history key: lowercase scheme
+// and host, no fragment, and "/" for an empty path
+func normalizeURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// splitPayloads returns a copy of record for storage, without the screenshot
+// and HAR that would dominate its size, and the HAR to keep separately
+func splitPayloads(record *models.StoredResult) (*models.StoredResult, *models.HAR) {
+	if record.Result == nil {
+		return record, nil
+	}
+	result := *record.Result
+	har := result.HAR
+	result.Screenshot = nil
+	result.HAR = nil
+
+	stored := *record
+	stored.Result = &result
+	return &stored, har
+}
@@ -0,0 +1,251 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+// loadMorePage only shows its second item after the button is clicked
+const loadMorePage = `<html><body>
+<ul id="items"><li>First item</li></ul>
+<button id="more" onclick="document.getElementById('items').insertAdjacentHTML('beforeend', '<li>Second item</li>')">Load more</button>
+</body></html>`
+
+func TestScrapeEndpoint_ActionValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.POST("/scrape", api.NewScrapeHandler(scraperService).HandleScrapePost)
+
+	for _, actions := range []string{
+		`[{"type": "hover", "selector": "a"}]`,
+		`[{"type": "click"}]`,
+		`[{"type": "type", "selector": "input"}]`,
+		`[{"type": "press", "key": "Hyper"}]`,
+		`[{"type": "select", "selector": "select"}]`,
+		`[{"type": "evaluate"}]`,
+		`[{"type": "wait"}]`,
+		`[{"type": "wait", "ms": 600000}]`,
+		`[{"type": "scroll", "times": -1}]`,
+		`[{"type": "waitForSelector", "selector": "main", "timeoutMs": -5}]`,
+	} {
+		w := httptest.NewRecorder()
+		body := `{"url": "https://example.com", "actions": ` + actions + `}`
+		req := httptest.NewRequest("POST", "/scrape", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_actions") {
+			t.Errorf("%s: expected 400 invalid_actions, got %d: %s", actions, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestScrape_ActionsRunBeforeCapture(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(loadMorePage))
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode: models.RenderModeStatic,
+		Actions: []models.BrowserAction{
+			{Type: models.ActionClick, Selector: "#more"},
+			{Type: models.ActionWaitForSelector, Selector: "#items li:nth-child(2)"},
+			{Type: models.ActionScroll, ToBottom: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if !strings.Contains(result.Markdown, "First item") {
+		t.Errorf("Expected the page to be converted, got:\n%s", result.Markdown)
+	}
+
+	// Without a local Chrome the page is fetched statically and the actions are reported as not run
+	if result.Mode != models.RenderModeBrowser {
+		if !strings.Contains(strings.Join(result.Warnings, "\n"), "Browser actions stopped") {
+			t.Errorf("Expected a warning that the actions did not run, got warnings %v", result.Warnings)
+		}
+		return
+	}
+	if !strings.Contains(result.Markdown, "Second item") {
+		t.Errorf("Expected content loaded by the click, got:\n%s", result.Markdown)
+	}
+}
+
+func TestScrape_FailedActionKeepsPage(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(loadMorePage))
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode: models.RenderModeBrowser,
+		Actions: []models.BrowserAction{
+			{Type: models.ActionClick, Selector: "#missing", TimeoutMs: 200},
+			{Type: models.ActionClick, Selector: "#more"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeBrowser {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+	if !strings.Contains(strings.Join(result.Warnings, "\n"), "Browser actions stopped") {
+		t.Errorf("Expected a warning for the failed action, got warnings %v", result.Warnings)
+	}
+	if strings.Contains(result.Markdown, "Second item") {
+		t.Errorf("Expected actions after the failure to be skipped, got:\n%s", result.Markdown)
+	}
+	if !strings.Contains(result.Markdown, "First item") {
+		t.Errorf("Expected the page to be kept, got:\n%s", result.Markdown)
+	}
+}
+
+func TestScrape_SlowActionsOutlastTabTimeout(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(loadMorePage))
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 2
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	// Together the actions take longer than the tab's own timeout
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode: models.RenderModeBrowser,
+		Actions: []models.BrowserAction{
+			{Type: models.ActionClick, Selector: "#more"},
+			{Type: models.ActionWait, Ms: 2500},
+			{Type: models.ActionEvaluate, Script: "new Promise(() => {})", TimeoutMs: 500},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeBrowser {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+	if !strings.Contains(result.Markdown, "Second item") {
+		t.Errorf("Expected the clicked page to be captured, got:\n%s", result.Markdown)
+	}
+	if !strings.Contains(strings.Join(result.Warnings, "\n"), "action 3 (evaluate) failed") {
+		t.Errorf("Expected the hung evaluate to time out on its own, got warnings %v", result.Warnings)
+	}
+}
+
+func TestScrape_CaptureAfterActionsOverrunBudget(t *testing.T) {
+	var lateLoads atomic.Int32
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			// A slow load eats into the time the actions were given
+			time.Sleep(2500 * time.Millisecond)
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><body><h1>Late</h1><div style="height:8000px"></div>
+<img id="late" loading="lazy" src="/late.svg" width="10" height="10"></body></html>`))
+		case "/late.svg":
+			lateLoads.Add(1)
+			w.Header().Set("Content-Type", "image/svg+xml")
+			w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg" width="10" height="10"/>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 1
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode:       models.RenderModeBrowser,
+		Wait:       &models.WaitOptions{Strategy: models.WaitDelay, Ms: 1},
+		Actions:    []models.BrowserAction{{Type: models.ActionEvaluate, Script: "new Promise(() => {})", TimeoutMs: 3000}},
+		Screenshot: &models.ScreenshotOptions{Selector: "#late"},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeBrowser {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+	if !strings.Contains(strings.Join(result.Warnings, "\n"), "action 1 (evaluate) failed") {
+		t.Fatalf("Expected the actions to run out the tab's time, got warnings %v", result.Warnings)
+	}
+
+	// Scrolling to the element loads the lazy image after the tab's time ran
+	// out; the interceptor must still let it through
+	if result.Screenshot == nil {
+		t.Fatalf("Expected a screenshot after the actions overran, got warnings %v", result.Warnings)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for lateLoads.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if lateLoads.Load() == 0 {
+		t.Error("Expected the image loaded during capture to reach the server")
+	}
+}
+
+func TestScrape_ActionRendersAreNotCached(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(loadMorePage))
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	if _, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode:    models.RenderModeBrowser,
+		Actions: []models.BrowserAction{{Type: models.ActionClick, Selector: "#more"}},
+	}); err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+
+	// A plain scrape must see the page as served, not the DOM the click left behind
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Mode: models.RenderModeBrowser})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Cached {
+		t.Error("Expected the render with actions to stay out of the cache")
+	}
+	if strings.Contains(result.Markdown, "Second item") || !strings.Contains(result.Markdown, "First item") {
+		t.Errorf("Expected the original page, got:\n%s", result.Markdown)
+	}
+}
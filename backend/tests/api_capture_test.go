@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+// newAPISite serves a page that loads its data from JSON endpoints with fetch and XHR
+func newAPISite() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><body><ul id="items"></ul><script>
+fetch('/api/items').then(r => r.json()).then(items => {
+  for (const item of items) {
+    const li = document.createElement('li');
+    li.textContent = item.name;
+    document.getElementById('items').appendChild(li);
+  }
+});
+fetch('/api/search', {method: 'POST', headers: {'Content-Type': 'application/json'}, body: '{"q":"pear"}'});
+fetch('/api/version');
+const xhr = new XMLHttpRequest();
+xhr.open('GET', '/tracking/ping');
+xhr.send();
+</script></body></html>`))
+		case "/api/items":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"name":"Apple"},{"name":"Pear"}]`))
+		case "/api/search":
+			w.Header().Set("Content-Type", "application/vnd.search+json")
+			w.Write([]byte(`{"hits":1}`))
+		case "/api/version":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(`v2`))
+		case "/tracking/ping":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestScrapeEndpoint_CaptureAPIValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/scrape", api.NewScrapeHandler(scraperService).HandleScrape)
+	router.POST("/scrape", api.NewScrapeHandler(scraperService).HandleScrapePost)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/scrape?url=https://example.com&captureApiTypes=json", nil))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_capture_api") {
+		t.Errorf("Expected 400 invalid_capture_api, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	body := `{"url":"https://example.com","captureApi":{"patterns":[""]}}`
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/scrape", strings.NewReader(body)))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_capture_api") {
+		t.Errorf("Expected 400 invalid_capture_api for an empty pattern, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestScrape_CapturesAPIResponses(t *testing.T) {
+	site := newAPISite()
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Wait:       &models.WaitOptions{Strategy: models.WaitNetworkIdle},
+		CaptureAPI: &models.APICaptureOptions{Patterns: []string{"*/api/*"}},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeBrowser {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+
+	captured := map[string]models.APIResponse{}
+	for _, resp := range result.APIResponses {
+		captured[strings.TrimPrefix(resp.URL, site.URL)] = resp
+	}
+	if len(captured) != 2 {
+		t.Fatalf("Expected the two JSON API responses, got %+v", result.APIResponses)
+	}
+
+	var items []struct{ Name string }
+	if err := json.Unmarshal(captured["/api/items"].Data, &items); err != nil || len(items) != 2 || items[1].Name != "Pear" {
+		t.Errorf("Expected the items as JSON data, got %s (%v)", captured["/api/items"].Data, err)
+	}
+	search := captured["/api/search"]
+	if search.Method != "POST" || search.RequestBody != `{"q":"pear"}` || search.ContentType != "application/vnd.search+json" {
+		t.Errorf("Expected the search request and its +json response, got %+v", search)
+	}
+	if len(result.Pages[0].APIResponses) != 0 {
+		t.Errorf("Expected the seed page's responses on the result only, got %+v", result.Pages[0].APIResponses)
+	}
+}
+
+func TestScrape_CaptureAPIByContentType(t *testing.T) {
+	site := newAPISite()
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Wait:       &models.WaitOptions{Strategy: models.WaitNetworkIdle},
+		CaptureAPI: &models.APICaptureOptions{ContentTypes: []string{"text/*"}},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeBrowser {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+	if len(result.APIResponses) != 1 || result.APIResponses[0].Body != "v2" || result.APIResponses[0].Data != nil {
+		t.Errorf("Expected only the plain text response, as text, got %+v", result.APIResponses)
+	}
+}
+
+func TestScrape_CaptureAPIWithoutBrowserWarns(t *testing.T) {
+	site := newAPISite()
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	// Warm the cache so cache=only has a copy to serve
+	if _, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Mode: models.RenderModeStatic}); err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode:       models.RenderModeStatic,
+		Cache:      models.CacheOnly,
+		CaptureAPI: &models.APICaptureOptions{},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if len(result.APIResponses) != 0 {
+		t.Errorf("Expected no API responses from the cache, got %+v", result.APIResponses)
+	}
+	if !strings.Contains(strings.Join(result.Warnings, "\n"), "API capture incomplete") {
+		t.Errorf("Expected an API capture warning, got %v", result.Warnings)
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+func TestScrapeEndpoint_BlockValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/scrape", api.NewScrapeHandler(scraperService).HandleScrape)
+
+	for _, query := range []string{
+		"block=video",
+		"block=image,script",
+		"blockDomains=https://ads.example.com",
+		"blockDomains=ads.example.com/path",
+		"blockDomains=*.example.com",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/scrape?url=https://example.com&"+query, nil))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_block") {
+			t.Errorf("%q: expected 400 invalid_block, got %d: %s", query, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestScrape_BlocksResourcesInBrowser(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]bool{}
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head>
+<link rel="stylesheet" href="/style.css">
+<script src="/tracker.js"></script>
+<script src="/app.js"></script>
+</head><body><h1>Light page</h1><img src="/photo.png"></body></html>`))
+		case "/style.css":
+			w.Header().Set("Content-Type", "text/css")
+			w.Write([]byte(`h1 { color: red }`))
+		case "/tracker.js", "/app.js":
+			w.Header().Set("Content-Type", "application/javascript")
+			w.Write([]byte(`void 0`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode: models.RenderModeBrowser,
+		Wait: &models.WaitOptions{Strategy: models.WaitNetworkIdle},
+		Block: &models.BlockOptions{
+			Resources: []models.ResourceKind{models.ResourceImage, models.ResourceStylesheet},
+			Patterns:  []string{"*/tracker.js"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeBrowser {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+	if !strings.Contains(result.Markdown, "Light page") {
+		t.Errorf("Expected the page itself to load, got:\n%s", result.Markdown)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, path := range []string{"/style.css", "/tracker.js", "/photo.png"} {
+		if requested[path] {
+			t.Errorf("Expected %s to be blocked before reaching the server", path)
+		}
+	}
+	if !requested["/app.js"] {
+		t.Error("Expected unblocked scripts to load")
+	}
+	for resourceType, want := range map[string]int{"image": 1, "stylesheet": 1, "script": 1} {
+		if result.Blocked[resourceType] != want {
+			t.Errorf("Expected %d blocked %s request(s), got %v", want, resourceType, result.Blocked)
+		}
+	}
+	if result.Pages[0].Blocked["script"] != 1 {
+		t.Errorf("Expected blocked counts on the page, got %v", result.Pages[0].Blocked)
+	}
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+)
+
+// newBrokenAppSite serves a single-page app whose bundle is missing and whose
+// inline script crashes, leaving the page blank
+func newBrokenAppSite() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head>
+<script src="/bundle.js"></script>
+<script src="/ads.js"></script>
+</head><body><div id="app"></div><script>
+console.log("booting", 2, {debug: true});
+console.warn("slow start");
+function render() { return window.App.mount("#app"); }
+render();
+</script></body></html>`))
+		case "/ads.js":
+			w.Header().Set("Content-Type", "application/javascript")
+			w.Write([]byte(`void 0`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestScrape_CollectsBrowserLog(t *testing.T) {
+	site := newBrokenAppSite()
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode:  models.RenderModeBrowser,
+		Wait:  &models.WaitOptions{Strategy: models.WaitNetworkIdle},
+		Block: &models.BlockOptions{Patterns: []string{"*/ads.js"}},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeBrowser {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+	browserLog := result.BrowserLog
+	if browserLog == nil || result.Pages[0].BrowserLog != nil {
+		t.Fatalf("Expected the seed page's browser log on the result only, got %+v and %+v", browserLog, result.Pages[0].BrowserLog)
+	}
+
+	levels := map[string]string{}
+	for _, msg := range browserLog.Console {
+		levels[msg.Text] = msg.Level
+	}
+	if levels["booting 2 Object"] != "log" || levels["slow start"] != "warning" {
+		t.Errorf("Expected the console messages with their levels, got %+v", browserLog.Console)
+	}
+
+	if len(browserLog.Exceptions) != 1 {
+		t.Fatalf("Expected one uncaught exception, got %+v", browserLog.Exceptions)
+	}
+	exception := browserLog.Exceptions[0]
+	if !strings.HasPrefix(exception.Message, "TypeError") || !strings.Contains(exception.Stack, "at render") {
+		t.Errorf("Expected the TypeError with its stack, got %+v", exception)
+	}
+
+	failed := map[string]models.FailedRequest{}
+	for _, req := range browserLog.FailedRequests {
+		failed[strings.TrimPrefix(req.URL, site.URL)] = req
+	}
+	if bundle := failed["/bundle.js"]; bundle.Status != http.StatusNotFound || bundle.ResourceType != "script" || bundle.Blocked {
+		t.Errorf("Expected the missing bundle as a 404 script, got %+v", bundle)
+	}
+	if ads := failed["/ads.js"]; !ads.Blocked || ads.Error == "" {
+		t.Errorf("Expected the blocked script marked as blocked, got %+v", ads)
+	}
+
+	if !strings.Contains(strings.Join(result.Warnings, "\n"), "Rendered page has no content (browser log: 1 uncaught exception") {
+		t.Errorf("Expected the blank page warning to summarize the browser log, got %v", result.Warnings)
+	}
+}
+
+func TestScrape_StaticPagesHaveNoBrowserLog(t *testing.T) {
+	site := newBrokenAppSite()
+	defer site.Close()
+
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Mode: models.RenderModeStatic})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.BrowserLog != nil || result.Pages[0].BrowserLog != nil {
+		t.Errorf("Expected no browser log for a static fetch, got %+v", result.BrowserLog)
+	}
+	if strings.Contains(strings.Join(result.Warnings, "\n"), "browser log") {
+		t.Errorf("Expected no browser log summary in a static fetch's warnings, got %v", result.Warnings)
+	}
+}
+
+func TestBrowserRenderError_SummarizesLog(t *testing.T) {
+	cause := errors.New("context deadline exceeded")
+	err := error(&services.BrowserRenderError{
+		Err: cause,
+		Log: &models.BrowserLog{
+			Exceptions:     []models.JSException{{Message: "TypeError: app is undefined"}},
+			FailedRequests: []models.FailedRequest{{URL: "https://example.com/app.js", Status: http.StatusNotFound}},
+		},
+	})
+	if got := err.Error(); got != "context deadline exceeded (browser log: 1 uncaught exception, 1 failed request)" {
+		t.Errorf("Expected the cause with a browser log summary, got %q", got)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("Expected the error to unwrap to its cause")
+	}
+
+	quiet := &services.BrowserRenderError{Err: cause, Log: &models.BrowserLog{Console: []models.ConsoleMessage{{Level: "log", Text: "hi"}}}}
+	if got := quiet.Error(); got != "context deadline exceeded" {
+		t.Errorf("Expected no summary for a log without errors, got %q", got)
+	}
+}
+
+func TestScrape_KeepsBrowserLogWhenRenderFails(t *testing.T) {
+	// The body never becomes visible, so the render times out after the script throws
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body style="display:none"><script>throw new Error("boot failed")</script></body></html>`))
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 2
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Mode: models.RenderModeBrowser})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	warnings := strings.Join(result.Warnings, "\n")
+	if !strings.Contains(warnings, "deadline exceeded") {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+	if result.Mode != models.RenderModeStatic {
+		t.Fatalf("Expected the static fallback, got mode %q", result.Mode)
+	}
+	if result.BrowserLog == nil || len(result.BrowserLog.Exceptions) != 1 {
+		t.Fatalf("Expected the failed render's browser log on the result, got %+v", result.BrowserLog)
+	}
+	if !strings.Contains(warnings, "(browser log: 1 uncaught exception)") {
+		t.Errorf("Expected the fallback warning to summarize the browser log, got %v", result.Warnings)
+	}
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/chromedp/chromedp"
+)
+
+// newTestPool creates a pool of headless browsers started from execPath,
+// or from the default Chrome when execPath is empty
+func newTestPool(t *testing.T, execPath string, maxTabs, recycleAfter int) *services.BrowserPool {
+	t.Helper()
+	opts := chromedp.DefaultExecAllocatorOptions[:]
+	if execPath != "" {
+		opts = append(opts, chromedp.ExecPath(execPath))
+	}
+	pool := services.NewBrowserPool(func() (context.Context, context.CancelFunc) {
+		return chromedp.NewExecAllocator(context.Background(), opts...)
+	}, false, maxTabs, 200*time.Millisecond, recycleAfter, 0)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestBrowserPool_FailedStartFreesSlot(t *testing.T) {
+	pool := newTestPool(t, "/nonexistent/chrome", 1, 0)
+
+	for i := 0; i < 3; i++ {
+		_, _, err := pool.Acquire(context.Background())
+		if err == nil {
+			t.Fatal("Expected starting a missing browser to fail")
+		}
+		if errors.Is(err, services.ErrBrowserBusy) {
+			t.Fatalf("Attempt %d: a failed start kept its tab slot: %v", i+1, err)
+		}
+	}
+	if stats := pool.Stats(); stats.Running || stats.OpenTabs != 0 || stats.MaxTabs != 1 {
+		t.Errorf("Expected an idle pool with one slot, got %+v", stats)
+	}
+}
+
+func TestBrowserPool_QueuesTabs(t *testing.T) {
+	pool := newTestPool(t, "", 1, 0)
+
+	_, release, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Skipf("Headless Chrome unavailable: %v", err)
+	}
+
+	if _, _, err := pool.Acquire(context.Background()); !errors.Is(err, services.ErrBrowserBusy) {
+		t.Errorf("Expected ErrBrowserBusy while the only tab is open, got %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := pool.Acquire(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the caller's cancellation while queued, got %v", err)
+	}
+
+	// A tab freed while another waits is handed over
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		release()
+	}()
+	tabCtx, releaseNext, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the queued request to get the freed tab, got %v", err)
+	}
+	defer releaseNext()
+	if err := chromedp.Run(tabCtx, chromedp.Navigate("about:blank")); err != nil {
+		t.Errorf("Expected a usable tab, got %v", err)
+	}
+}
+
+func TestBrowserPool_RestartsAfterCrash(t *testing.T) {
+	pool := newTestPool(t, "", 2, 0)
+
+	tabCtx, release, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Skipf("Headless Chrome unavailable: %v", err)
+	}
+	if err := chromedp.Run(tabCtx); err != nil {
+		t.Fatalf("Failed to open tab: %v", err)
+	}
+	if err := chromedp.FromContext(tabCtx).Browser.Process().Kill(); err != nil {
+		t.Fatalf("Failed to kill the browser: %v", err)
+	}
+	release()
+
+	// The crash is noticed and a fresh browser started for the next tab
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.Stats().Restarts == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	tabCtx, release, err = pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the browser to restart, got %v", err)
+	}
+	defer release()
+	if err := chromedp.Run(tabCtx, chromedp.Navigate("about:blank")); err != nil {
+		t.Errorf("Expected a usable tab after the restart, got %v", err)
+	}
+	if stats := pool.Stats(); stats.Restarts != 1 {
+		t.Errorf("Expected one restart, got %+v", stats)
+	}
+}
+
+func TestBrowserPool_RecyclesAfterPages(t *testing.T) {
+	pool := newTestPool(t, "", 2, 2)
+
+	for i := 0; i < 3; i++ {
+		tabCtx, release, err := pool.Acquire(context.Background())
+		if err != nil {
+			t.Skipf("Headless Chrome unavailable: %v", err)
+		}
+		if err := chromedp.Run(tabCtx, chromedp.Navigate("about:blank")); err != nil {
+			t.Fatalf("Tab %d unusable: %v", i+1, err)
+		}
+		release()
+	}
+	if stats := pool.Stats(); stats.Recycled != 1 || stats.Pages != 1 || stats.Restarts != 0 {
+		t.Errorf("Expected one recycle after two pages, got %+v", stats)
+	}
+}
+
+func TestBrowserPool_SlowStartDoesNotBlock(t *testing.T) {
+	// A "browser" that never announces its DevTools endpoint hangs the launch
+	hung := filepath.Join(t.TempDir(), "hung-chrome")
+	if err := os.WriteFile(hung, []byte("#!/bin/sh\nsleep 60\n"), 0o755); err != nil {
+		t.Fatalf("Failed to write the fake browser: %v", err)
+	}
+	pool := newTestPool(t, hung, 2, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, _, err := pool.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the caller's deadline while the browser starts, got %v", err)
+	}
+
+	// Stats (and so /health) answer while the launch is still stuck
+	done := make(chan services.BrowserPoolStats, 1)
+	go func() { done <- pool.Stats() }()
+	select {
+	case stats := <-done:
+		if stats.State != services.BrowserStarting || stats.OpenTabs != 0 {
+			t.Errorf("Expected a starting browser with no tabs held, got %+v", stats)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stats blocked on the browser launch")
+	}
+}
@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+// newETagSite serves a page with an ETag and counts full and conditional responses
+func newETagSite() (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	var full, notModified atomic.Int32
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Cached</title></head><body><p>Hello</p></body></html>`)
+	}))
+	return site, &full, &notModified
+}
+
+func TestResponseCache_ServesAndRevalidates(t *testing.T) {
+	site, full, notModified := newETagSite()
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.CacheTTLSeconds = 1
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	ctx := context.Background()
+	opts := models.ScrapeOptions{Mode: models.RenderModeStatic}
+
+	first, err := scraperService.Scrape(ctx, site.URL+"/", opts)
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if first.Cached {
+		t.Errorf("Expected the first scrape to be fetched, not cached")
+	}
+
+	second, err := scraperService.Scrape(ctx, site.URL+"/#ignored", opts)
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if !second.Cached || second.Markdown != first.Markdown {
+		t.Errorf("Expected the second scrape to be served from the cache, got cached=%v", second.Cached)
+	}
+	if full.Load() != 1 {
+		t.Errorf("Expected one full fetch, got %d", full.Load())
+	}
+
+	// Once stale, the entry is revalidated with a conditional GET
+	time.Sleep(1100 * time.Millisecond)
+	third, err := scraperService.Scrape(ctx, site.URL+"/", opts)
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if !third.Cached || notModified.Load() != 1 || full.Load() != 1 {
+		t.Errorf("Expected a 304 revalidation, got cached=%v full=%d notModified=%d", third.Cached, full.Load(), notModified.Load())
+	}
+
+	// Bypass always fetches
+	opts.Cache = models.CacheBypass
+	bypassed, err := scraperService.Scrape(ctx, site.URL+"/", opts)
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if bypassed.Cached || full.Load() != 2 {
+		t.Errorf("Expected cache=bypass to fetch, got cached=%v full=%d", bypassed.Cached, full.Load())
+	}
+}
+
+func TestScrapeEndpoint_CacheOnlyMiss(t *testing.T) {
+	site, full, _ := newETagSite()
+	defer site.Close()
+
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/scrape", api.NewScrapeHandler(scraperService).HandleScrape)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/scrape?cache=only&url="+url.QueryEscape(site.URL+"/"), nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status 504, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if full.Load() != 0 {
+		t.Errorf("Expected cache=only not to fetch the page, got %d fetches", full.Load())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/scrape?cache=sometimes&url="+url.QueryEscape(site.URL+"/"), nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown cache policy, got %d", w.Code)
+	}
+}
+
+func TestResponseCache_KeysRendersOnBrowserOptions(t *testing.T) {
+	site, _, _ := newETagSite()
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.CacheTTLSeconds = 60
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	ctx := context.Background()
+	blocked := models.ScrapeOptions{
+		Mode:  models.RenderModeAuto,
+		Block: &models.BlockOptions{Patterns: []string{"*/ads.js", "*/tracker.js"}, Domains: []string{"ads.example.com"}},
+	}
+	if _, err := scraperService.Scrape(ctx, site.URL+"/", blocked); err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+
+	// The same rules in another order and case share the entry
+	reordered := models.ScrapeOptions{
+		Mode:  models.RenderModeAuto,
+		Block: &models.BlockOptions{Patterns: []string{"*/TRACKER.js", "*/ads.js"}, Domains: []string{".Ads.Example.com"}},
+	}
+	result, err := scraperService.Scrape(ctx, site.URL+"/", reordered)
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if !result.Cached {
+		t.Error("Expected equivalent block rules to be served from the cache")
+	}
+
+	for name, opts := range map[string]models.ScrapeOptions{
+		"no blocking": {Mode: models.RenderModeAuto, Block: &models.BlockOptions{}},
+		"longer wait": {Mode: models.RenderModeAuto, Block: blocked.Block, Wait: &models.WaitOptions{Strategy: models.WaitDelay, Ms: 3000}},
+	} {
+		result, err := scraperService.Scrape(ctx, site.URL+"/", opts)
+		if err != nil {
+			t.Fatalf("%s: scrape failed: %v", name, err)
+		}
+		if result.Cached {
+			t.Errorf("%s: expected a render with different browser options to miss the cache", name)
+		}
+	}
+}
+
+func TestResponseCache_SkipsBrowserFallbacks(t *testing.T) {
+	site, full, _ := newETagSite()
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.CacheTTLSeconds = 60
+	cfg.ChromeWSURL = "ws://127.0.0.1:1/devtools/browser/missing"
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	ctx := context.Background()
+	opts := models.ScrapeOptions{Mode: models.RenderModeBrowser}
+	for i := 1; i <= 2; i++ {
+		result, err := scraperService.Scrape(ctx, site.URL+"/", opts)
+		if err != nil {
+			t.Fatalf("Scrape %d failed: %v", i, err)
+		}
+		if result.Cached || result.Mode != models.RenderModeStatic {
+			t.Errorf("Scrape %d: expected a fresh static fallback, got cached=%v mode=%q", i, result.Cached, result.Mode)
+		}
+		if !strings.Contains(strings.Join(result.Warnings, "\n"), "falling back to static fetch") {
+			t.Errorf("Scrape %d: expected the fallback warning, got %v", i, result.Warnings)
+		}
+	}
+	if full.Load() != 2 {
+		t.Errorf("Expected the fallback page to be fetched each time, got %d fetches", full.Load())
+	}
+}
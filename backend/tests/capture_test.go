@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+func TestScreenshotEndpoint_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/screenshot", api.NewCaptureHandler(scraperService).HandleScreenshot)
+
+	tests := []struct {
+		query     string
+		errorType string
+	}{
+		{"", "bad_request"},
+		{"url=https://example.com&format=gif", "invalid_screenshot"},
+		{"url=https://example.com&quality=50", "invalid_screenshot"},
+		{"url=https://example.com&format=jpeg&quality=101", "invalid_screenshot"},
+		{"url=https://example.com&fullPage=yes", "invalid_screenshot"},
+		{"url=https://example.com&fullPage=true&selector=main", "invalid_screenshot"},
+		{"url=https://example.com&width=0", "invalid_screenshot"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/screenshot?"+tt.query, nil))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.errorType) {
+			t.Errorf("%q: expected 400 %s, got %d: %s", tt.query, tt.errorType, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestScreenshot_CapturesImage(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body><h1 id="title">Snap</h1></body></html>`))
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	shot, err := scraperService.Screenshot(context.Background(), site.URL+"/", models.ScreenshotOptions{Selector: "#title"})
+	if err != nil {
+		t.Skipf("Headless Chrome unavailable: %v", err)
+	}
+	if shot.Format != models.ImagePNG || !bytes.HasPrefix(shot.Data, []byte("\x89PNG")) {
+		t.Errorf("Expected a PNG image, got format %q starting %q", shot.Format, shot.Data[:min(8, len(shot.Data))])
+	}
+}
+
+func TestScrape_ScreenshotFallsBackWithWarning(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body><h1>Snap</h1></body></html>`))
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode:       models.RenderModeStatic,
+		Screenshot: &models.ScreenshotOptions{Format: models.ImageJPEG},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if !strings.Contains(result.Markdown, "Snap") {
+		t.Errorf("Expected the page to be converted, got:\n%s", result.Markdown)
+	}
+
+	// Without a local Chrome the page is fetched statically and the screenshot is reported missing
+	if result.Screenshot == nil {
+		if !strings.Contains(strings.Join(result.Warnings, "\n"), "Screenshot failed") {
+			t.Errorf("Expected a screenshot or a warning, got warnings %v", result.Warnings)
+		}
+		return
+	}
+	if result.Mode != models.RenderModeBrowser || result.Screenshot.Format != models.ImageJPEG || !bytes.HasPrefix(result.Screenshot.Data, []byte("\xff\xd8")) {
+		t.Errorf("Expected a JPEG rendered in the browser, got mode %q format %q", result.Mode, result.Screenshot.Format)
+	}
+}
+
+func TestPDFEndpoint_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/pdf", api.NewCaptureHandler(scraperService).HandlePDF)
+
+	for _, query := range []string{
+		"url=https://example.com&paper=b9",
+		"url=https://example.com&paper=a4&width=8&height=11",
+		"url=https://example.com&width=8",
+		"url=https://example.com&margin=-1",
+		"url=https://example.com&marginTop=NaN",
+		"url=https://example.com&landscape=sideways",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/pdf?"+query, nil))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_pdf") {
+			t.Errorf("%q: expected 400 invalid_pdf, got %d: %s", query, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestPDF_PrintsDocument(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Archive me</title></head><body><h1>Record</h1></body></html>`))
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	margin := 0.5
+	data, err := scraperService.PDF(context.Background(), site.URL+"/", models.PDFOptions{
+		Paper:          "A4",
+		Landscape:      true,
+		MarginTop:      &margin,
+		FooterTemplate: `<div style="font-size:8px"><span class="pageNumber"></span></div>`,
+	})
+	if err != nil {
+		t.Skipf("Headless Chrome unavailable: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		t.Errorf("Expected a PDF document, got %q", data[:min(8, len(data))])
+	}
+}
@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/config"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+)
+
+// newCrawlSite serves a small linked site: / -> /a, /b ; /a -> /c ; /b -> external
+func newCrawlSite() *httptest.Server {
+	pages := map[string]string{
+		"/":  `<a href="/a">A</a><a href="/b#top">B</a><a href="/a">A again</a>`,
+		"/a": `<a href="/c">C</a><a href="/">Home</a>`,
+		"/b": `<a href="https://example.org/elsewhere">External</a>`,
+		"/c": `<p>Leaf</p>`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<html><head><title>Page %s</title></head><body>%s</body></html>", r.URL.Path, body)
+	}))
+}
+
+// newLocalConfig returns a config for scraping httptest servers: no politeness
+// delay, and loopback allowed through the URL guard
+func newLocalConfig() *config.Config {
+	cfg := config.Load()
+	cfg.ScraperDelaySeconds = 0
+	cfg.AllowedCIDRs = []string{"127.0.0.0/8", "::1"}
+	return cfg
+}
+
+func newCrawlService(t *testing.T) *services.ScraperService {
+	t.Helper()
+	cfg := newLocalConfig()
+	scraperService := services.NewScraperService(cfg)
+	t.Cleanup(scraperService.Close)
+	return scraperService
+}
+
+func TestCrawl_BreadthFirst(t *testing.T) {
+	site := newCrawlSite()
+	defer site.Close()
+
+	result, err := newCrawlService(t).Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Depth: 3, MaxPages: 10})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+
+	want := []struct {
+		path   string
+		parent string
+		depth  int
+	}{
+		{"/", "", 1},
+		{"/a", "/", 2},
+		{"/b", "/", 2},
+		{"/c", "/a", 3},
+	}
+	if len(result.Pages) != len(want) {
+		t.Fatalf("Expected %d pages, got %d: %+v", len(want), len(result.Pages), result.Pages)
+	}
+	for i, w := range want {
+		page := result.Pages[i]
+		if page.URL != site.URL+w.path {
+			t.Errorf("Page %d: expected URL %s, got %s", i, site.URL+w.path, page.URL)
+		}
+		if w.parent != "" && page.ParentURL != site.URL+w.parent {
+			t.Errorf("Page %d: expected parent %s, got %s", i, site.URL+w.parent, page.ParentURL)
+		}
+		if page.Depth != w.depth {
+			t.Errorf("Page %d: expected depth %d, got %d", i, w.depth, page.Depth)
+		}
+		if page.Title != "Page "+w.path {
+			t.Errorf("Page %d: expected title %q, got %q", i, "Page "+w.path, page.Title)
+		}
+	}
+
+	if result.Title != "Page /" {
+		t.Errorf("Expected top-level title to mirror the seed page, got %q", result.Title)
+	}
+}
+
+func TestCrawl_DepthOneVisitsSeedOnly(t *testing.T) {
+	site := newCrawlSite()
+	defer site.Close()
+
+	result, err := newCrawlService(t).Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Depth: 1})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if len(result.Pages) != 1 {
+		t.Fatalf("Expected 1 page, got %d", len(result.Pages))
+	}
+	if len(result.Links) != 3 {
+		t.Errorf("Expected 3 links on the seed page, got %d", len(result.Links))
+	}
+}
+
+func TestCrawl_MaxPagesCap(t *testing.T) {
+	site := newCrawlSite()
+	defer site.Close()
+
+	result, err := newCrawlService(t).Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Depth: 3, MaxPages: 2})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if len(result.Pages) != 2 {
+		t.Fatalf("Expected crawl to stop at 2 pages, got %d", len(result.Pages))
+	}
+}
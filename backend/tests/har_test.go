@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/Michael-Obele/web-scraper-backend/src/storage"
+	"github.com/gin-gonic/gin"
+)
+
+func TestScrapeEndpoint_HARValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/scrape", api.NewScrapeHandler(scraperService).HandleScrape)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/scrape?url=https://example.com&har=maybe", nil))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_har") {
+		t.Errorf("Expected 400 invalid_har, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestScrape_RecordsHAR(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/", http.StatusFound)
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><title>Traffic</title><script src="/app.js?v=2"></script></head>
+<body><h1>Traffic</h1><img src="/missing.png"></body></html>`))
+		case "/app.js":
+			w.Header().Set("Content-Type", "application/javascript")
+			w.Write([]byte(`void 0`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/old", models.ScrapeOptions{
+		Mode: models.RenderModeBrowser,
+		Wait: &models.WaitOptions{Strategy: models.WaitNetworkIdle},
+		HAR:  true,
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeBrowser {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+	if result.HAR == nil {
+		t.Fatalf("Expected the seed page's HAR on the result, got warnings %v", result.Warnings)
+	}
+
+	log := result.HAR.Log
+	if log.Version != "1.2" || len(log.Pages) != 1 || log.Pages[0].Title != "Traffic" {
+		t.Errorf("Expected a HAR 1.2 log for the page, got %+v", log.Pages)
+	}
+
+	entries := map[string]models.HAREntry{}
+	for _, entry := range log.Entries {
+		entries[strings.TrimPrefix(entry.Request.URL, site.URL)] = entry
+	}
+	if entry := entries["/old"]; entry.Response.Status != http.StatusFound || entry.Response.RedirectURL != site.URL+"/" {
+		t.Errorf("Expected the redirect as its own entry, got %+v", entry.Response)
+	}
+	page := entries["/"]
+	if page.Response.Status != http.StatusOK || page.ResourceType != "document" || page.Response.Content.Size == 0 {
+		t.Errorf("Expected the document entry with its size, got %+v", page)
+	}
+	if page.Time < 0 || page.Timings.Wait < 0 || page.StartedDateTime == "" {
+		t.Errorf("Expected timings on the document entry, got %+v", page.Timings)
+	}
+	script := entries["/app.js?v=2"]
+	if len(script.Request.QueryString) != 1 || script.Request.QueryString[0].Value != "2" {
+		t.Errorf("Expected the script's query string, got %+v", script.Request.QueryString)
+	}
+	if !hasHeader(script.Response.Headers, "Content-Type", "application/javascript") {
+		t.Errorf("Expected the script's response headers, got %+v", script.Response.Headers)
+	}
+	if entries["/missing.png"].Response.Status != http.StatusNotFound {
+		t.Errorf("Expected the missing image's 404, got %+v", entries["/missing.png"].Response)
+	}
+}
+
+func TestScrape_HARWithoutBrowserWarns(t *testing.T) {
+	site := newCrawlSite()
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	// Warm the cache so cache=only has a copy to serve
+	if _, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Mode: models.RenderModeStatic}); err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode:  models.RenderModeStatic,
+		Cache: models.CacheOnly,
+		HAR:   true,
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.HAR != nil {
+		t.Errorf("Expected no HAR from the cache, got %+v", result.HAR)
+	}
+	if !strings.Contains(strings.Join(result.Warnings, "\n"), "HAR capture failed") {
+		t.Errorf("Expected a HAR warning, got %v", result.Warnings)
+	}
+}
+
+func TestHistoryEndpoints_DownloadHAR(t *testing.T) {
+	store := storage.NewMemoryStore(0)
+	ctx := context.Background()
+	har := &models.HAR{Log: models.HARLog{Version: "1.2", Entries: []models.HAREntry{}}}
+	for id, result := range map[string]*models.ScrapeResult{
+		"with-har":    {Title: "Recorded", HAR: har},
+		"without-har": {Title: "Plain"},
+	} {
+		if err := store.Save(ctx, &models.StoredResult{ID: id, URL: "https://example.com/", Result: result}); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/results/:id/har", api.NewHistoryHandler(store).HandleGetResultHAR)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/results/with-har/har", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="with-har.har"` {
+		t.Errorf("Expected a download filename, got %q", got)
+	}
+	var downloaded models.HAR
+	if err := json.Unmarshal(w.Body.Bytes(), &downloaded); err != nil || downloaded.Log.Version != "1.2" {
+		t.Errorf("Expected the stored HAR, got %s (%v)", w.Body.String(), err)
+	}
+
+	for path, errorType := range map[string]string{
+		"/results/without-har/har": "har_not_found",
+		"/results/unknown/har":     "result_not_found",
+	} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), errorType) {
+			t.Errorf("%s: expected 404 %s, got %d: %s", path, errorType, w.Code, w.Body.String())
+		}
+	}
+}
+
+// hasHeader reports whether headers contain name with value, ignoring the name's case
+func hasHeader(headers []models.HARNameValue, name, value string) bool {
+	for _, header := range headers {
+		if strings.EqualFold(header.Name, name) && header.Value == value {
+			return true
+		}
+	}
+	return false
+}
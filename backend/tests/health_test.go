@@ -1,12 +1,18 @@
 package tests
 
 import (
+	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Michael-Obele/web-scraper-backend/handlers"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
 	"github.com/gin-gonic/gin"
 )
 
@@ -47,3 +53,158 @@ func TestHealthCheck(t *testing.T) {
 		t.Error("Expected timestamp to be a number")
 	}
 }
+
+func TestHealthCheck_ReportsBrowser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/health", handlers.NewHealthCheck(scraperService.BrowserStats))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+	var response struct {
+		Status  string                    `json:"status"`
+		Browser services.BrowserPoolStats `json:"browser"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+
+	// The local browser starts on first use
+	if w.Code != http.StatusOK || response.Status != "healthy" {
+		t.Errorf("Expected a healthy service, got %d %q", w.Code, response.Status)
+	}
+	if response.Browser.Mode != "local" || response.Browser.State != services.BrowserIdle || response.Browser.MaxTabs < 1 {
+		t.Errorf("Expected an idle local browser, got %+v", response.Browser)
+	}
+}
+
+func TestHealthCheck_ReconnectingBrowserIsHealthy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for state, want := range map[string]string{
+		services.BrowserReconnecting: "healthy",
+		services.BrowserStarting:     "healthy",
+		services.BrowserDisconnected: "degraded",
+	} {
+		stats := func() services.BrowserPoolStats { return services.BrowserPoolStats{State: state} }
+		router := gin.New()
+		router.GET("/health", handlers.NewHealthCheck(stats))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+		var response struct {
+			Status string `json:"status"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse JSON response: %v", err)
+		}
+		if response.Status != want {
+			t.Errorf("Expected %q while the browser is %s, got %q", want, state, response.Status)
+		}
+	}
+}
+
+func TestHealthCheck_RemoteBrowserDisconnected(t *testing.T) {
+	// Nothing listens on the DevTools URL, so every connection attempt fails
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve a port: %v", err)
+	}
+	wsURL := "ws://" + listener.Addr().String() + "/devtools/browser/missing"
+	listener.Close()
+
+	gin.SetMode(gin.TestMode)
+	cfg := newLocalConfig()
+	cfg.ChromeWSURL = wsURL
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for scraperService.BrowserStats().State != services.BrowserDisconnected && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	router := gin.New()
+	router.GET("/health", handlers.NewHealthCheck(scraperService.BrowserStats))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+	var response struct {
+		Status  string                    `json:"status"`
+		Browser services.BrowserPoolStats `json:"browser"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	if response.Status != "degraded" {
+		t.Errorf("Expected a degraded service, got %q", response.Status)
+	}
+	if response.Browser.Mode != "remote" || response.Browser.State != services.BrowserDisconnected || response.Browser.LastError == "" {
+		t.Errorf("Expected a disconnected remote browser with its error, got %+v", response.Browser)
+	}
+
+	// Scrapes still work, falling back to static fetching
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body><h1>Still here</h1></body></html>`))
+	}))
+	defer site.Close()
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Mode: models.RenderModeBrowser})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeStatic || !strings.Contains(result.Markdown, "Still here") {
+		t.Errorf("Expected a static fallback, got mode %q:\n%s", result.Mode, result.Markdown)
+	}
+}
+
+func TestHealthCheck_UnresponsiveRemoteBrowser(t *testing.T) {
+	// The DevTools URL accepts connections but never answers the handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve a port: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	gin.SetMode(gin.TestMode)
+	cfg := newLocalConfig()
+	cfg.ChromeWSURL = "ws://" + listener.Addr().String() + "/devtools/browser/silent"
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/health", handlers.NewHealthCheck(scraperService.BrowserStats))
+
+	// /health answers while the dial hangs
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+		done <- w
+	}()
+	select {
+	case w := <-done:
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"state":"starting"`) {
+			t.Errorf("Expected the browser reported as starting, got %d: %s", w.Code, w.Body.String())
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("/health blocked on the remote browser dial")
+	}
+}
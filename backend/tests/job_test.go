@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+func newJobRouter(t *testing.T) (*gin.Engine, *services.JobService) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	cfg := newLocalConfig()
+	cfg.JobWorkers = 1
+	scraperService := services.NewScraperService(cfg)
+	jobService := services.NewJobService(cfg, scraperService)
+	t.Cleanup(func() {
+		jobService.Shutdown(context.Background())
+		scraperService.Close()
+	})
+
+	jobHandler := api.NewJobHandler(jobService)
+	router := gin.New()
+	router.POST("/jobs", jobHandler.HandleCreateJob)
+	router.GET("/jobs/:id", jobHandler.HandleGetJob)
+	router.DELETE("/jobs/:id", jobHandler.HandleCancelJob)
+	return router, jobService
+}
+
+func doJobRequest(t *testing.T, router *gin.Engine, method, path string, body any) (*httptest.ResponseRecorder, models.Job) {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var job models.Job
+	json.Unmarshal(w.Body.Bytes(), &job)
+	return w, job
+}
+
+func waitForJob(t *testing.T, router *gin.Engine, id string) models.Job {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		_, job := doJobRequest(t, router, "GET", "/jobs/"+id, nil)
+		if job.Status.Finished() {
+			return job
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Job %s did not finish in time", id)
+	return models.Job{}
+}
+
+func TestJobs_RunToCompletion(t *testing.T) {
+	site := newCrawlSite()
+	defer site.Close()
+	router, _ := newJobRouter(t)
+
+	w, job := doJobRequest(t, router, "POST", "/jobs", models.ScrapeRequest{
+		URL:           site.URL + "/",
+		ScrapeOptions: models.ScrapeOptions{Depth: 2, Mode: models.RenderModeStatic},
+	})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if job.ID == "" || job.Status != models.JobStatusQueued {
+		t.Fatalf("Expected a queued job with an ID, got %+v", job)
+	}
+
+	job = waitForJob(t, router, job.ID)
+	if job.Status != models.JobStatusSucceeded {
+		t.Fatalf("Expected job to succeed, got %s (%s)", job.Status, job.Error)
+	}
+	if job.Result == nil || len(job.Result.Pages) != 3 {
+		t.Fatalf("Expected a 3 page result, got %+v", job.Result)
+	}
+	if job.Progress.PagesVisited != 3 {
+		t.Errorf("Expected progress to report 3 pages, got %+v", job.Progress)
+	}
+}
+
+func TestJobs_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		fmt.Fprint(w, "<html><body>late</body></html>")
+	}))
+	defer slow.Close()
+	defer close(release)
+	router, _ := newJobRouter(t)
+
+	_, job := doJobRequest(t, router, "POST", "/jobs", models.ScrapeRequest{
+		URL:           slow.URL + "/",
+		ScrapeOptions: models.ScrapeOptions{Mode: models.RenderModeStatic},
+	})
+
+	w, _ := doJobRequest(t, router, "DELETE", "/jobs/"+job.ID, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 on cancel, got %d", w.Code)
+	}
+
+	job = waitForJob(t, router, job.ID)
+	if job.Status != models.JobStatusCancelled {
+		t.Errorf("Expected cancelled job, got %s", job.Status)
+	}
+}
+
+func TestJobs_ExpireWithoutNewSubmissions(t *testing.T) {
+	site := newCrawlSite()
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.JobTTLSeconds = 1
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+	jobService := services.NewJobService(cfg, scraperService)
+	defer jobService.Shutdown(context.Background())
+
+	job, err := jobService.Submit(models.ScrapeRequest{
+		URL:           site.URL + "/",
+		ScrapeOptions: models.ScrapeOptions{Mode: models.RenderModeStatic},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !job.Status.Finished() && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		if job, err = jobService.Get(job.ID); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+	}
+	if job.Status != models.JobStatusSucceeded {
+		t.Fatalf("Expected job to succeed, got %s (%s)", job.Status, job.Error)
+	}
+
+	// Finished jobs are dropped after the TTL even though nothing else is submitted
+	time.Sleep(2500 * time.Millisecond)
+	if _, err := jobService.Get(job.ID); !errors.Is(err, services.ErrJobNotFound) {
+		t.Errorf("Expected the finished job to expire, got %v", err)
+	}
+}
+
+func TestJobs_Validation(t *testing.T) {
+	router, _ := newJobRouter(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           any
+		expectedStatus int
+	}{
+		{"Missing URL", "POST", "/jobs", models.ScrapeRequest{}, http.StatusBadRequest},
+		{"Invalid mode", "POST", "/jobs", models.ScrapeRequest{URL: "https://example.com", ScrapeOptions: models.ScrapeOptions{Mode: "fast"}}, http.StatusBadRequest},
+		{"Unknown job", "GET", "/jobs/does-not-exist", nil, http.StatusNotFound},
+		{"Cancel unknown job", "DELETE", "/jobs/does-not-exist", nil, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := doJobRequest(t, router, tt.method, tt.path, tt.body)
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
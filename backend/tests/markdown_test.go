@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/markdown"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// goldenPageURL is the URL golden inputs are treated as being served from
+const goldenPageURL = "https://example.com/articles/page.html"
+
+// TestMarkdown_Golden converts every testdata/markdown/*.html file and compares
+// the output with the matching .md file. Run with -update to regenerate them.
+func TestMarkdown_Golden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "markdown", "*.html"))
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("No golden inputs found: %v", err)
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".html")
+		t.Run(name, func(t *testing.T) {
+			file, err := os.Open(input)
+			if err != nil {
+				t.Fatalf("Failed to open input: %v", err)
+			}
+			defer file.Close()
+
+			doc, err := goquery.NewDocumentFromReader(file)
+			if err != nil {
+				t.Fatalf("Failed to parse input: %v", err)
+			}
+			doc.Url, _ = url.Parse(goldenPageURL)
+			got := markdown.Convert(doc)
+
+			golden := strings.TrimSuffix(input, ".html") + ".md"
+			if *updateGolden {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("Markdown mismatch for %s\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+			}
+		})
+	}
+}
+
+func TestScrape_ResolvesAgainstFinalURL(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new/page", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body><p><a href="next">Next</a> <img src="pic.png" alt="Pic"></p></body></html>`))
+	}))
+	defer site.Close()
+
+	result, err := newCrawlService(t).Scrape(context.Background(), site.URL+"/old", models.ScrapeOptions{Mode: models.RenderModeStatic})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+
+	if result.Pages[0].FinalURL != site.URL+"/new/page" {
+		t.Errorf("Expected final URL %s, got %q", site.URL+"/new/page", result.Pages[0].FinalURL)
+	}
+	want := "[Next](" + site.URL + "/new/next) ![Pic](" + site.URL + "/new/pic.png)"
+	if !strings.Contains(result.Markdown, want) {
+		t.Errorf("Expected URLs resolved against the redirect target, got:\n%s", result.Markdown)
+	}
+	if len(result.Links) != 1 || result.Links[0].Href != site.URL+"/new/next" {
+		t.Errorf("Expected links resolved against the redirect target, got %+v", result.Links)
+	}
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/extract"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+const metadataPage = `<html lang="en-GB"><head>
+<title>Launch day</title>
+<meta name="description" content="We shipped the thing.">
+<meta name="keywords" content="launch, product , news">
+<meta property="og:title" content="Launch day!">
+<meta property="og:image" content="https://example.com/cover.png">
+<meta property="og:image" content="https://example.com/second.png">
+<meta property="article:published_time" content="2024-05-01T09:00:00Z">
+<meta name="twitter:card" content="summary_large_image">
+<link rel="canonical" href="/blog/launch">
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [
+	{"@type": "NewsArticle", "headline": "Launch day", "author": [{"@type": "Person", "name": "Ada Lovelace"}], "dateModified": "2024-05-02"},
+	{"@type": "Organization", "name": "Example Corp"}
+]}
+</script>
+<script type="application/ld+json">{ not json</script>
+</head><body>
+<div itemscope itemtype="https://schema.org/Product">
+	<span itemprop="name">Widget</span>
+	<img itemprop="image" src="/widget.png">
+	<div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+		<span itemprop="price" content="9.99">$9.99</span>
+		<time itemprop="validFrom" datetime="2024-05-01">May 1st</time>
+	</div>
+</div>
+<div vocab="https://schema.org/" typeof="Person" resource="#ada">
+	<span property="name">Ada Lovelace</span>
+	<a property="url" href="/ada">Profile</a>
+</div>
+</body></html>`
+
+func TestMetadata_Extract(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(metadataPage))
+	if err != nil {
+		t.Fatalf("Failed to parse page: %v", err)
+	}
+	doc.Url, _ = url.Parse("https://example.com/blog/launch?ref=home")
+
+	meta, err := extract.Metadata(doc)
+	if err == nil || !strings.Contains(err.Error(), "1 malformed JSON-LD") {
+		t.Errorf("Expected the malformed JSON-LD block to be reported, got %v", err)
+	}
+
+	checks := map[string][2]string{
+		"description": {meta.Description, "We shipped the thing."},
+		"canonical":   {meta.Canonical, "https://example.com/blog/launch"},
+		"language":    {meta.Language, "en-GB"},
+		"author":      {meta.Author, "Ada Lovelace"},
+		"published":   {meta.Published, "2024-05-01T09:00:00Z"},
+		"modified":    {meta.Modified, "2024-05-02"},
+		"og:image":    {meta.OpenGraph["og:image"], "https://example.com/cover.png"},
+		"twitter":     {meta.Twitter["twitter:card"], "summary_large_image"},
+	}
+	for name, check := range checks {
+		if check[0] != check[1] {
+			t.Errorf("Expected %s %q, got %q", name, check[1], check[0])
+		}
+	}
+	if !reflect.DeepEqual(meta.Keywords, []string{"launch", "product", "news"}) {
+		t.Errorf("Unexpected keywords: %q", meta.Keywords)
+	}
+
+	if len(meta.JSONLD) != 2 || meta.JSONLD[1]["name"] != "Example Corp" || meta.JSONLD[1]["@context"] != "https://schema.org" {
+		t.Errorf("Expected the @graph to be flattened with its context, got %+v", meta.JSONLD)
+	}
+
+	if len(meta.Microdata) != 1 {
+		t.Fatalf("Expected one top-level microdata item, got %+v", meta.Microdata)
+	}
+	product := meta.Microdata[0]
+	if product.Type[0] != "https://schema.org/Product" || product.Properties["name"][0] != "Widget" || product.Properties["image"][0] != "https://example.com/widget.png" {
+		t.Errorf("Unexpected product item: %+v", product)
+	}
+	offer, ok := product.Properties["offers"][0].(models.StructuredItem)
+	if !ok || offer.Properties["price"][0] != "9.99" || offer.Properties["validFrom"][0] != "2024-05-01" {
+		t.Errorf("Expected a nested offer item, got %+v", product.Properties["offers"])
+	}
+
+	if len(meta.RDFa) != 1 {
+		t.Fatalf("Expected one RDFa item, got %+v", meta.RDFa)
+	}
+	person := meta.RDFa[0]
+	if person.Type[0] != "https://schema.org/Person" || person.ID != "#ada" || person.Properties["url"][0] != "https://example.com/ada" {
+		t.Errorf("Unexpected RDFa item: %+v", person)
+	}
+}
+
+func TestScrape_IncludesMetadata(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><meta property="og:title" content="Fallback title"><meta name="description" content="About"></head><body><p>Hi</p></body></html>`))
+	}))
+	defer site.Close()
+
+	result, err := newCrawlService(t).Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Mode: models.RenderModeStatic})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Metadata == nil || result.Metadata.Description != "About" {
+		t.Fatalf("Expected the seed page's metadata on the result, got %+v", result.Metadata)
+	}
+	if result.Title != "Fallback title" {
+		t.Errorf("Expected og:title to stand in for a missing <title>, got %q", result.Title)
+	}
+}
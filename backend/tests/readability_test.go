@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/extract"
+	"github.com/Michael-Obele/web-scraper-backend/src/markdown"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gin-gonic/gin"
+)
+
+const articlePage = `<html><head><title>Story</title></head><body>
+<div class="cookie-banner">We use cookies to improve your experience. Accept all cookies?</div>
+<header class="site-header"><a href="/">Home</a> <a href="/news">News</a> <a href="/about">About</a></header>
+<div id="layout">
+	<div class="sidebar"><h3>Popular</h3><a href="/1">One</a><a href="/2">Two</a></div>
+	<div class="post-body">
+		<h1>The headline</h1>
+		<p>The first paragraph of the story is long enough to count, with commas, clauses, and detail.</p>
+		<p>A second paragraph continues the story, adding more context, quotes, and the kind of text a reader wants.</p>
+		<p>A third paragraph wraps things up, and mentions <a href="/source">a source</a> in passing.</p>
+	</div>
+	<div class="links"><a href="/a">Link A</a> <a href="/b">Link B</a> <a href="/c">Link C</a> <a href="/d">Link D</a></div>
+</div>
+<div class="footer">Copyright Example Corp. All rights reserved.</div>
+</body></html>`
+
+func TestMainContent_DropsPageFurniture(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(articlePage))
+	if err != nil {
+		t.Fatalf("Failed to parse page: %v", err)
+	}
+
+	main, ok := extract.MainContent(doc)
+	if !ok {
+		t.Fatalf("Expected main content to be found")
+	}
+	got := markdown.Convert(main)
+
+	for _, want := range []string{"# The headline", "first paragraph", "third paragraph", "[a source](/source)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected main content to contain %q, got:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"cookies", "About", "Popular", "Link A", "Copyright"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("Expected main content to drop %q, got:\n%s", unwanted, got)
+		}
+	}
+
+	if !strings.Contains(markdown.Convert(doc), "Copyright") {
+		t.Errorf("Expected the original document to be left untouched")
+	}
+}
+
+func TestMainContent_ShortPageFallsBack(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><body><nav><a href="/">Home</a></nav><p>Hi</p></body></html>`))
+	if _, ok := extract.MainContent(doc); ok {
+		t.Errorf("Expected no main content on a near-empty page")
+	}
+}
+
+func TestMainContent_TiesPickFirstCandidate(t *testing.T) {
+	// Two equally scored candidates in separate wrappers, so neither is kept as the other's sibling
+	paragraph := "paragraph of equal length, with the same commas, the same clauses, and enough text to pass as the main content"
+	page := `<html><body>
+<div><div><p>First ` + paragraph + `.</p><p>First ` + paragraph + `.</p></div></div>
+<div><div><p>Later ` + paragraph + `.</p><p>Later ` + paragraph + `.</p></div></div>
+</body></html>`
+
+	for i := 0; i < 20; i++ {
+		doc, _ := goquery.NewDocumentFromReader(strings.NewReader(page))
+		main, ok := extract.MainContent(doc)
+		if !ok {
+			t.Fatalf("Expected main content to be found")
+		}
+		got := markdown.Convert(main)
+		if !strings.Contains(got, "First") || strings.Contains(got, "Later") {
+			t.Fatalf("Expected the first of the tied candidates, got:\n%s", got)
+		}
+	}
+}
+
+func TestScrapeEndpoint_ContentMode(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(articlePage))
+	}))
+	defer site.Close()
+
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/scrape", api.NewScrapeHandler(scraperService).HandleScrape)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/scrape?mode=static&content=main&url="+site.URL+"/", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var result models.ScrapeResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if strings.Contains(result.Markdown, "Copyright") || !strings.Contains(result.Markdown, "The headline") {
+		t.Errorf("Expected only the main content in the markdown, got:\n%s", result.Markdown)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/scrape?content=summary&url="+site.URL+"/", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown content mode, got %d", w.Code)
+	}
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+)
+
+func newRenderModeSite() *httptest.Server {
+	pages := map[string]string{
+		"/plain":    `<html><head><title>Plain</title></head><body><h1>Plain page</h1><p>Server rendered.</p></body></html>`,
+		"/spa":      `<html><head><title>SPA</title><script src="/bundle.js"></script></head><body><div id="root"></div></body></html>`,
+		"/noscript": `<html><head><title>NS</title></head><body><noscript>You need to enable JavaScript to run this app.</noscript><p>Loading</p></body></html>`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestScrape_RenderModes(t *testing.T) {
+	site := newRenderModeSite()
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 5
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		mode       models.RenderMode
+		escalation bool
+	}{
+		{"Auto keeps plain HTML static", "/plain", models.RenderModeAuto, false},
+		{"Static never escalates", "/spa", models.RenderModeStatic, false},
+		{"Auto escalates framework shells", "/spa", models.RenderModeAuto, true},
+		{"Auto escalates noscript warnings", "/noscript", models.RenderModeAuto, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := scraperService.Scrape(context.Background(), site.URL+tt.path, models.ScrapeOptions{Mode: tt.mode})
+			if err != nil {
+				t.Fatalf("Scrape failed: %v", err)
+			}
+
+			// Without a local Chrome the escalation falls back to the static HTML with a warning
+			escalated := result.Mode == models.RenderModeBrowser
+			for _, warning := range result.Warnings {
+				if strings.Contains(warning, "JS-rendered") {
+					escalated = true
+				}
+			}
+			if escalated != tt.escalation {
+				t.Errorf("Expected escalation=%v, got mode %q with warnings %v", tt.escalation, result.Mode, result.Warnings)
+			}
+			if !tt.escalation && result.Mode != models.RenderModeStatic {
+				t.Errorf("Expected static mode, got %q", result.Mode)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+func newRobotsSite(robots string) (*httptest.Server, *int) {
+	robotsHits := 0
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsHits++
+			fmt.Fprint(w, robots)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><title>%s</title></head><body><a href="/private/x">P</a><a href="/public">Q</a></body></html>`, r.URL.Path)
+	}))
+	return site, &robotsHits
+}
+
+func TestRobotsChecker_CachesAndEvaluates(t *testing.T) {
+	site, hits := newRobotsSite("User-agent: *\nDisallow: /private\nCrawl-delay: 3\n")
+	defer site.Close()
+
+	checker := services.NewRobotsChecker(time.Hour, nil)
+
+	public, _ := url.Parse(site.URL + "/public")
+	delay, err := checker.Check(context.Background(), public, "test-agent")
+	if err != nil {
+		t.Fatalf("Expected /public to be allowed, got %v", err)
+	}
+	if delay != 3*time.Second {
+		t.Errorf("Expected Crawl-delay of 3s, got %s", delay)
+	}
+
+	private, _ := url.Parse(site.URL + "/private/page")
+	_, err = checker.Check(context.Background(), private, "test-agent")
+	var robotsErr *services.RobotsDisallowedError
+	if !errors.As(err, &robotsErr) {
+		t.Fatalf("Expected RobotsDisallowedError, got %v", err)
+	}
+
+	if *hits != 1 {
+		t.Errorf("Expected robots.txt to be fetched once, got %d fetches", *hits)
+	}
+}
+
+func TestScrapeEndpoint_RobotsDisallowed(t *testing.T) {
+	site, _ := newRobotsSite("User-agent: *\nDisallow: /private\n")
+	defer site.Close()
+
+	gin.SetMode(gin.TestMode)
+	cfg := newLocalConfig()
+	cfg.IgnoreRobotsTxt = false
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/scrape", api.NewScrapeHandler(scraperService).HandleScrape)
+
+	req, _ := http.NewRequest("GET", "/scrape?url="+url.QueryEscape(site.URL+"/private/page"), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d. Body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCrawl_SkipsDisallowedPages(t *testing.T) {
+	site, _ := newRobotsSite("User-agent: *\nDisallow: /private\n")
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.IgnoreRobotsTxt = false
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Depth: 2, MaxPages: 10})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	for _, page := range result.Pages {
+		if page.URL == site.URL+"/private/x" {
+			t.Errorf("Expected disallowed page to be skipped")
+		}
+	}
+	if len(result.Pages) != 2 {
+		t.Errorf("Expected seed and /public pages, got %d pages", len(result.Pages))
+	}
+}
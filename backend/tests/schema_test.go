@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/extract"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gin-gonic/gin"
+)
+
+const catalogPage = `<html><body>
+<h1>  Spring catalog  </h1>
+<ul id="products">
+	<li class="product"><a href="/p/1">Kettle</a><span class="price">$1,299.50</span><span class="sku">SKU: K-100</span></li>
+	<li class="product"><a href="/p/2">Toaster</a><span class="price">Call us</span><span class="sku">SKU: T-200</span></li>
+</ul>
+</body></html>`
+
+func TestSchema_Extract(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(catalogPage))
+	if err != nil {
+		t.Fatalf("Failed to parse page: %v", err)
+	}
+	doc.Url, _ = url.Parse("https://shop.example.com/catalog")
+
+	schema, err := extract.CompileSchema([]models.ExtractField{
+		{Name: "heading", XPath: "//h1", Transforms: []models.ExtractTransform{{Type: models.TransformTrim}}},
+		{Name: "missing", Selector: ".nothing"},
+		{Name: "products", Selector: "li.product", List: true, Fields: []models.ExtractField{
+			{Name: "name", Selector: "a"},
+			{Name: "url", Selector: "a", Attr: "href"},
+			{Name: "price", Selector: ".price", Transforms: []models.ExtractTransform{{Type: models.TransformNumber}}},
+			{Name: "sku", XPath: "./span[@class='sku']", Transforms: []models.ExtractTransform{{Type: models.TransformRegex, Pattern: `SKU: (\S+)`}}},
+		}},
+		{Name: "hrefs", XPath: "//li/a/@href", List: true},
+		{Name: "count", XPath: "count(//li[@class='product'])"},
+		{Name: "firstSku", XPath: "substring-after(string(//span[@class='sku']), 'SKU: ')"},
+		{Name: "hasPrices", XPath: "boolean(//span[@class='price'])", List: true},
+		{Name: "invalidNumber", XPath: "number(//h1)"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to compile schema: %v", err)
+	}
+
+	got, _ := json.Marshal(schema.Extract(doc))
+	want := `{"count":2,"firstSku":"K-100","hasPrices":[true],"heading":"Spring catalog",` +
+		`"hrefs":["https://shop.example.com/p/1","https://shop.example.com/p/2"],"invalidNumber":null,"missing":null,"products":[` +
+		`{"name":"Kettle","price":1299.5,"sku":"K-100","url":"https://shop.example.com/p/1"},` +
+		`{"name":"Toaster","price":null,"sku":"T-200","url":"https://shop.example.com/p/2"}]}`
+	if string(got) != want {
+		t.Errorf("Unexpected extraction\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestSchema_CompileErrors(t *testing.T) {
+	cases := map[string][]models.ExtractField{
+		`field "": name is required`:                {{Selector: "a"}},
+		`field "a": use either selector or xpath`:   {{Name: "a", Selector: "a", XPath: "//a"}},
+		`field "a": invalid selector`:               {{Name: "a", Selector: "a[["}},
+		`field "a": invalid xpath`:                  {{Name: "a", XPath: "//a[@"}},
+		`field "a.b": transform 1: unknown type`:    {{Name: "a", Fields: []models.ExtractField{{Name: "b", Transforms: []models.ExtractTransform{{Type: "upper"}}}}}},
+		`field "a": the number transform must come`: {{Name: "a", Transforms: []models.ExtractTransform{{Type: models.TransformNumber}, {Type: models.TransformTrim}}}},
+		`field "a": duplicate name`:                 {{Name: "a"}, {Name: "a"}},
+		`field "a": xpath returns a value`:          {{Name: "a", XPath: "count(//a)", Attr: "href"}},
+	}
+	for want, fields := range cases {
+		_, err := extract.CompileSchema(fields)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestScrapeEndpoint_PostWithSchema(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(catalogPage))
+	}))
+	defer site.Close()
+
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.POST("/scrape", api.NewScrapeHandler(scraperService).HandleScrapePost)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/scrape", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"url": "` + site.URL + `/", "mode": "static", "extract": [{"name": "names", "selector": "li a", "list": true}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var result models.ScrapeResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if !reflect.DeepEqual(result.Extracted["names"], []any{"Kettle", "Toaster"}) {
+		t.Errorf("Expected extracted names, got %+v", result.Extracted)
+	}
+	if !strings.Contains(result.Markdown, "Spring catalog") {
+		t.Errorf("Expected markdown alongside the extracted fields, got:\n%s", result.Markdown)
+	}
+
+	w = post(`{"url": "` + site.URL + `/", "extract": [{"name": "bad", "xpath": "//a[@"}]}`)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_extract") {
+		t.Errorf("Expected 400 invalid_extract for a broken schema, got %d: %s", w.Code, w.Body.String())
+	}
+}
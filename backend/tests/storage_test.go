@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/Michael-Obele/web-scraper-backend/src/storage"
+	"github.com/gin-gonic/gin"
+)
+
+func newStoredResult(id, rawURL string, fetchedAt time.Time) *models.StoredResult {
+	return &models.StoredResult{
+		ID:        id,
+		URL:       rawURL,
+		FetchedAt: fetchedAt,
+		Result:    &models.ScrapeResult{ID: id, Title: "Result " + id},
+	}
+}
+
+func TestBoltStore_HistoryNewestFirst(t *testing.T) {
+	store, err := storage.NewBoltStore(filepath.Join(t.TempDir(), "scrapes.db"), 0)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []*models.StoredResult{
+		newStoredResult("old", "https://Example.com/page", base),
+		newStoredResult("new", "https://example.com/page#section", base.Add(time.Hour)),
+		newStoredResult("longer", "https://example.com/pages", base.Add(2*time.Hour)),
+	}
+	for _, record := range records {
+		if err := store.Save(ctx, record); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	entries, err := store.History(ctx, "https://example.com/page", 10)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != "new" || entries[1].ID != "old" {
+		t.Fatalf("Expected [new old], got %+v", entries)
+	}
+
+	limited, _ := store.History(ctx, "https://example.com/page", 1)
+	if len(limited) != 1 || limited[0].ID != "new" {
+		t.Errorf("Expected limit to keep only the newest entry, got %+v", limited)
+	}
+
+	got, err := store.Get(ctx, "old")
+	if err != nil || got.Result.Title != "Result old" {
+		t.Errorf("Expected stored result to round-trip, got %+v (%v)", got, err)
+	}
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestHistoryEndpoints_ServeSavedScrape(t *testing.T) {
+	site := newCrawlSite()
+	defer site.Close()
+
+	store := storage.NewMemoryStore(0)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+	scraperService.SetResultStore(store)
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Depth: 1})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.ID == "" {
+		t.Fatalf("Expected saved scrape to have an ID")
+	}
+
+	gin.SetMode(gin.TestMode)
+	handler := api.NewHistoryHandler(store)
+	router := gin.New()
+	router.GET("/history", handler.HandleHistory)
+	router.GET("/results/:id", handler.HandleGetResult)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/history?url="+url.QueryEscape(site.URL+"/"), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var history struct {
+		Results []models.HistoryEntry `json:"results"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("Failed to decode history: %v", err)
+	}
+	if len(history.Results) != 1 || history.Results[0].ID != result.ID || history.Results[0].ContentHash == "" {
+		t.Fatalf("Expected one history entry for %s, got %+v", result.ID, history.Results)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/results/"+result.ID, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var stored models.StoredResult
+	if err := json.Unmarshal(w.Body.Bytes(), &stored); err != nil {
+		t.Fatalf("Failed to decode stored result: %v", err)
+	}
+	if stored.Result == nil || stored.Result.Title != "Page /" {
+		t.Errorf("Expected stored result to contain the scrape, got %+v", stored.Result)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/results/unknown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown result, got %d", w.Code)
+	}
+}
+
+func TestResultStores_KeepNewestWithoutLargePayloads(t *testing.T) {
+	bolt, err := storage.NewBoltStore(filepath.Join(t.TempDir(), "scrapes.db"), 2)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer bolt.Close()
+
+	for name, store := range map[string]storage.ResultStore{
+		"memory": storage.NewMemoryStore(2),
+		"bolt":   bolt,
+	} {
+		ctx := context.Background()
+		base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		har := &models.HAR{Log: models.HARLog{Version: "1.2", Entries: []models.HAREntry{}}}
+		screenshot := &models.Screenshot{Format: "png", Data: []byte("\x89PNG")}
+
+		var saved []*models.StoredResult
+		for i, id := range []string{"first", "second", "third"} {
+			record := newStoredResult(id, "https://example.com/", base.Add(time.Duration(i)*time.Hour))
+			record.Result.Screenshot = screenshot
+			record.Result.HAR = har
+			record.Result.Pages = []models.PageResult{{URL: "https://example.com/"}}
+			if err := store.Save(ctx, record); err != nil {
+				t.Fatalf("%s: Save failed: %v", name, err)
+			}
+			saved = append(saved, record)
+		}
+
+		if saved[2].Result.Screenshot == nil || saved[2].Result.HAR == nil {
+			t.Errorf("%s: Expected Save to leave the caller's result intact", name)
+		}
+		if _, err := store.Get(ctx, "first"); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("%s: Expected the oldest result to be removed, got %v", name, err)
+		}
+		if _, err := store.HAR(ctx, "first"); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("%s: Expected the oldest result's HAR to be removed, got %v", name, err)
+		}
+		entries, _ := store.History(ctx, "https://example.com/", 10)
+		if len(entries) != 2 || entries[0].ID != "third" || entries[1].ID != "second" || entries[0].PageCount != 1 {
+			t.Errorf("%s: Expected history of [third second], got %+v", name, entries)
+		}
+
+		got, err := store.Get(ctx, "third")
+		if err != nil {
+			t.Fatalf("%s: Get failed: %v", name, err)
+		}
+		if got.Result.Screenshot != nil || got.Result.HAR != nil {
+			t.Errorf("%s: Expected the stored result without its screenshot or HAR, got %+v", name, got.Result)
+		}
+		if stored, err := store.HAR(ctx, "third"); err != nil || stored == nil || stored.Log.Version != "1.2" {
+			t.Errorf("%s: Expected the HAR stored separately, got %+v (%v)", name, stored, err)
+		}
+	}
+}
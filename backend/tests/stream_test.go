@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/config"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+func TestScrapeStream_EmitsEvents(t *testing.T) {
+	site := newCrawlSite()
+	defer site.Close()
+
+	gin.SetMode(gin.TestMode)
+	cfg := newLocalConfig()
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/scrape/stream", api.NewScrapeHandler(scraperService).HandleScrapeStream)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/scrape/stream?mode=static&depth=2&url=" + url.QueryEscape(site.URL+"/"))
+	if err != nil {
+		t.Fatalf("Stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Errorf("Expected text/event-stream, got %q", ct)
+	}
+
+	counts := map[string]int{}
+	var last string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "event:"); ok {
+			counts[name]++
+			last = name
+		}
+	}
+
+	if counts["fetch_started"] != 3 || counts["page_completed"] != 3 || counts["links"] != 3 {
+		t.Errorf("Expected 3 fetch_started, page_completed and links events, got %v", counts)
+	}
+	if last != "done" {
+		t.Errorf("Expected the stream to end with done, got %q", last)
+	}
+}
+
+func TestScrapeStream_ValidationErrorsAreJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(config.Load())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/scrape/stream", api.NewScrapeHandler(scraperService).HandleScrapeStream)
+
+	req, _ := http.NewRequest("GET", "/scrape/stream?url=ftp://example.com", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
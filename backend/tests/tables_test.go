@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+)
+
+func scrapeHTML(t *testing.T, body string) *models.ScrapeResult {
+	t.Helper()
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<html><head><title>Test</title></head><body>%s</body></html>", body)
+	}))
+	defer site.Close()
+
+	result, err := newCrawlService(t).Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Mode: models.RenderModeStatic})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	return result
+}
+
+func TestTables_MarkdownAndJSON(t *testing.T) {
+	result := scrapeHTML(t, `
+		<div>
+			<table>
+				<caption>Prices</caption>
+				<thead><tr><th>Item</th><th>Price</th><th>Notes</th></tr></thead>
+				<tbody>
+					<tr><td>Apple</td><td>1</td><td>red | green</td></tr>
+					<tr><td colspan="2">Pear (seasonal)</td><td>
+						yellow</td></tr>
+				</tbody>
+			</table>
+		</div>`)
+
+	wantMarkdown := "Prices\n\n" +
+		"| Item | Price | Notes |\n" +
+		"| --- | --- | --- |\n" +
+		"| Apple | 1 | red \\| green |\n" +
+		"| Pear (seasonal) |  | yellow |\n"
+	if !strings.Contains(result.Markdown, wantMarkdown) {
+		t.Errorf("Expected GFM table in markdown, got:\n%s", result.Markdown)
+	}
+
+	want := []models.Table{{
+		Caption: "Prices",
+		Headers: []string{"Item", "Price", "Notes"},
+		Rows: [][]string{
+			{"Apple", "1", "red | green"},
+			{"Pear (seasonal)", "", "yellow"},
+		},
+	}}
+	if !reflect.DeepEqual(result.Tables, want) {
+		t.Errorf("Expected tables %+v, got %+v", want, result.Tables)
+	}
+}
+
+func TestTables_HeaderlessAndNested(t *testing.T) {
+	result := scrapeHTML(t, `
+		<table>
+			<tr><td>Sidebar</td><td>
+				<table><tr><th>K</th><th>V</th></tr><tr><td>a</td><td>1</td></tr></table>
+			</td></tr>
+		</table>
+		<table><tr><td>x</td><td>y</td></tr></table>`)
+
+	if strings.Contains(result.Markdown, "| Sidebar") {
+		t.Errorf("Expected layout table not to be rendered as a GFM table, got:\n%s", result.Markdown)
+	}
+	if !strings.Contains(result.Markdown, "Sidebar\n\n| K | V |\n| --- | --- |\n| a | 1 |\n") {
+		t.Errorf("Expected nested data table to be rendered, got:\n%s", result.Markdown)
+	}
+	if !strings.Contains(result.Markdown, "|  |  |\n| --- | --- |\n| x | y |\n") {
+		t.Errorf("Expected headerless table to get a blank header row, got:\n%s", result.Markdown)
+	}
+
+	if len(result.Tables) != 2 {
+		t.Fatalf("Expected the two data tables, got %+v", result.Tables)
+	}
+	if result.Tables[1].Headers != nil || !reflect.DeepEqual(result.Tables[1].Rows, [][]string{{"x", "y"}}) {
+		t.Errorf("Expected headerless table rows only, got %+v", result.Tables[1])
+	}
+}
+
+func TestTables_RowspanAndEmpty(t *testing.T) {
+	result := scrapeHTML(t, `
+		<table><tr><td> </td></tr><tr></tr></table>
+		<table></table>
+		<table>
+			<thead><tr><th>Team</th><th>Player</th><th>Goals</th></tr></thead>
+			<tbody>
+				<tr><td rowspan="2">Red</td><td>Ann</td><td>3</td></tr>
+				<tr><td>Bo</td><td rowspan="5">1</td></tr>
+				<tr><td>Blue</td><td>Cy</td></tr>
+			</tbody>
+			<tfoot><tr><td>Total</td><td colspan="2">4</td></tr></tfoot>
+		</table>`)
+
+	want := []models.Table{{
+		Headers: []string{"Team", "Player", "Goals"},
+		Rows: [][]string{
+			{"Red", "Ann", "3"},
+			{"", "Bo", "1"},
+			{"Blue", "Cy", ""},
+			{"Total", "4", ""},
+		},
+	}}
+	if !reflect.DeepEqual(result.Tables, want) {
+		t.Errorf("Expected the rowspans expanded and the empty tables skipped, got %+v", result.Tables)
+	}
+
+	wantMarkdown := "| Team | Player | Goals |\n" +
+		"| --- | --- | --- |\n" +
+		"| Red | Ann | 3 |\n" +
+		"|  | Bo | 1 |\n" +
+		"| Blue | Cy |  |\n" +
+		"| Total | 4 |  |\n"
+	if !strings.Contains(result.Markdown, wantMarkdown) {
+		t.Errorf("Expected the rowspans expanded in markdown, got:\n%s", result.Markdown)
+	}
+	if strings.Count(result.Markdown, "--- |\n") != 1 {
+		t.Errorf("Expected no markdown for the empty tables, got:\n%s", result.Markdown)
+	}
+}
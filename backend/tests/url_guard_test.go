@@ -0,0 +1,237 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/config"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+func TestURLGuard_BlocksReservedRanges(t *testing.T) {
+	guard := services.NewURLGuard(nil, nil)
+
+	tests := []struct {
+		url     string
+		blocked bool
+	}{
+		{"http://127.0.0.1/", true},
+		{"http://localhost:8080/", true},
+		{"http://169.254.169.254/latest/meta-data/", true},
+		{"http://10.1.2.3/", true},
+		{"http://172.16.0.1/", true},
+		{"http://192.168.1.1/", true},
+		{"http://100.100.100.200/", true},
+		{"http://[::1]/", true},
+		{"http://[fd00:ec2::254]/", true},
+		{"http://[::ffff:127.0.0.1]/", true},
+		{"file:///etc/passwd", true},
+		{"http://93.184.215.14/", false},
+		{"http://[2606:4700::1111]/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := guard.CheckURL(context.Background(), tt.url)
+			var blocked *services.BlockedTargetError
+			if got := errors.As(err, &blocked); got != tt.blocked {
+				t.Errorf("Expected blocked=%v, got error %v", tt.blocked, err)
+			}
+		})
+	}
+}
+
+func TestURLGuard_AllowAndDenyLists(t *testing.T) {
+	guard := services.NewURLGuard([]string{"10.0.0.0/8"}, []string{"10.9.0.0/16", "93.184.215.14"})
+
+	if err := guard.CheckURL(context.Background(), "http://10.1.2.3/"); err != nil {
+		t.Errorf("Expected allow list to permit 10.1.2.3, got %v", err)
+	}
+	if err := guard.CheckURL(context.Background(), "http://10.9.1.1/"); err == nil {
+		t.Error("Expected deny list to win over allow list for 10.9.1.1")
+	}
+	if err := guard.CheckURL(context.Background(), "http://93.184.215.14/"); err == nil {
+		t.Error("Expected deny list to block a public address")
+	}
+}
+
+func TestScrapeEndpoint_BlockedTarget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(config.Load())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/scrape", api.NewScrapeHandler(scraperService).HandleScrape)
+
+	for _, target := range []string{"http://127.0.0.1:9/", "http://169.254.169.254/latest/meta-data/"} {
+		req, _ := http.NewRequest("GET", "/scrape?url="+url.QueryEscape(target), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected status 403, got %d. Body: %s", target, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestScrape_BlocksRedirectToPrivateAddress(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, "<html><body>ok</body></html>")
+	}))
+	defer site.Close()
+
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	_, err := scraperService.Scrape(context.Background(), site.URL+"/redirect", models.ScrapeOptions{Mode: models.RenderModeStatic})
+	var blocked *services.BlockedTargetError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("Expected BlockedTargetError for the redirect hop, got %v", err)
+	}
+}
+
+// newHandshakeCounter serves on addr and counts WebSocket handshakes that reach it
+func newHandshakeCounter(t *testing.T, addr string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("Cannot listen on %s: %v", addr, err)
+	}
+	var handshakes atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			handshakes.Add(1)
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, &handshakes
+}
+
+// proxyConnect asks the proxy at proxyAddr to tunnel to target and returns its answer
+func proxyConnect(t *testing.T, proxyAddr, target string) (*http.Response, net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", proxyAddr)
+	if err != nil {
+		t.Fatalf("Failed to reach the proxy: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", target, target)
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("Failed to read the CONNECT response: %v", err)
+	}
+	return resp, conn, reader
+}
+
+func TestBrowserProxy_BlocksWebSocketToLoopback(t *testing.T) {
+	target, handshakes := newHandshakeCounter(t, "127.0.0.1:0")
+	proxy, err := services.NewBrowserProxy(services.NewURLGuard(nil, nil))
+	if err != nil {
+		t.Fatalf("Failed to start the proxy: %v", err)
+	}
+	defer proxy.Close()
+	proxyAddr := strings.TrimPrefix(proxy.URL(), "http://")
+
+	// Browsers tunnel ws:// and wss:// through CONNECT
+	resp, _, _ := proxyConnect(t, proxyAddr, target.Listener.Addr().String())
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected CONNECT to loopback to be refused with 403, got %d", resp.StatusCode)
+	}
+
+	// A plain proxied upgrade request is refused as well
+	req, _ := http.NewRequest("GET", target.URL+"/socket", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	proxyURL, _ := url.Parse(proxy.URL())
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("Proxied request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a proxied upgrade to loopback to be refused with 403, got %d", resp.StatusCode)
+	}
+
+	if n := handshakes.Load(); n != 0 {
+		t.Errorf("Expected no handshake to reach the loopback server, got %d", n)
+	}
+}
+
+func TestBrowserProxy_TunnelsAllowedTargets(t *testing.T) {
+	target, handshakes := newHandshakeCounter(t, "127.0.0.1:0")
+	proxy, err := services.NewBrowserProxy(services.NewURLGuard([]string{"127.0.0.0/8"}, nil))
+	if err != nil {
+		t.Fatalf("Failed to start the proxy: %v", err)
+	}
+	defer proxy.Close()
+	proxyAddr := strings.TrimPrefix(proxy.URL(), "http://")
+
+	resp, conn, reader := proxyConnect(t, proxyAddr, target.Listener.Addr().String())
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected CONNECT to an allowed target to succeed, got %d", resp.StatusCode)
+	}
+	fmt.Fprint(conn, "GET / HTTP/1.1\r\nHost: target\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+	if _, err := http.ReadResponse(reader, nil); err != nil {
+		t.Fatalf("Failed to read through the tunnel: %v", err)
+	}
+	if n := handshakes.Load(); n != 1 {
+		t.Errorf("Expected the handshake to reach the allowed server, got %d", n)
+	}
+}
+
+func TestScrape_BrowserWebSocketToDeniedAddress(t *testing.T) {
+	// Linux routes all of 127.0.0.0/8 to loopback, so one address can be denied while the page's is allowed
+	target, handshakes := newHandshakeCounter(t, "127.0.0.2:0")
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><body><p>Socket</p><script>new WebSocket("ws://%s/socket");</script></body></html>`,
+			target.Listener.Addr().String())
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.DeniedCIDRs = []string{"127.0.0.2"}
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode: models.RenderModeBrowser,
+		Wait: &models.WaitOptions{Strategy: models.WaitDelay, Ms: 1000},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeBrowser {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := handshakes.Load(); n != 0 {
+		t.Errorf("Expected the page's WebSocket to the denied address to be blocked, got %d handshakes", n)
+	}
+}
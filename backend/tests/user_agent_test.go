@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+)
+
+func TestUserAgentRotator_Strategies(t *testing.T) {
+	agents := []string{"agent-a", "agent-b", "agent-c"}
+
+	roundRobin := services.NewUserAgentRotator(services.UAStrategyRoundRobin, agents)
+	for i := 0; i < 6; i++ {
+		if got := roundRobin.Next("example.com"); got != agents[i%3] {
+			t.Errorf("Round-robin pick %d: expected %s, got %s", i, agents[i%3], got)
+		}
+	}
+
+	sticky := services.NewUserAgentRotator(services.UAStrategySticky, agents)
+	first := sticky.Next("Example.com")
+	for i := 0; i < 10; i++ {
+		if got := sticky.Next("example.com"); got != first {
+			t.Fatalf("Sticky rotator changed agent for the same host: %s -> %s", first, got)
+		}
+	}
+	used := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		used[sticky.Next(fmt.Sprintf("host-%d.example.com", i))] = true
+	}
+	if len(used) < 2 {
+		t.Errorf("Expected the sticky rotator to spread hosts across agents, got %v", used)
+	}
+
+	random := services.NewUserAgentRotator(services.UAStrategyRandom, agents)
+	for i := 0; i < 10; i++ {
+		got := random.Next("example.com")
+		if got != "agent-a" && got != "agent-b" && got != "agent-c" {
+			t.Fatalf("Random rotator returned unknown agent %q", got)
+		}
+	}
+}
+
+func TestScrape_ReportsUserAgent(t *testing.T) {
+	var seen []string
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		seen = append(seen, r.UserAgent())
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>UA</title></head><body><a href="/next">next</a></body></html>`)
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ScraperUserAgents = []string{"agent-a", "agent-b"}
+	cfg.ScraperUAStrategy = services.UAStrategyRoundRobin
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{Depth: 2})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+
+	if len(result.Pages) != 2 {
+		t.Fatalf("Expected 2 pages, got %d", len(result.Pages))
+	}
+	if result.UserAgent != "agent-a" || result.Pages[1].UserAgent != "agent-b" {
+		t.Errorf("Expected agents to rotate across pages, got %q then %q", result.UserAgent, result.Pages[1].UserAgent)
+	}
+	for i, page := range result.Pages {
+		if i < len(seen) && seen[i] != page.UserAgent {
+			t.Errorf("Page %d reported %q but the server saw %q", i, page.UserAgent, seen[i])
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Michael-Obele/web-scraper-backend/src/api"
+	"github.com/Michael-Obele/web-scraper-backend/src/models"
+	"github.com/Michael-Obele/web-scraper-backend/src/services"
+	"github.com/gin-gonic/gin"
+)
+
+// latePage adds its content a moment after loading
+const latePage = `<html><body><div id="app">Loading</div>
+<script>setTimeout(() => { document.getElementById("app").innerHTML = '<p id="ready">Rendered late</p>' }, 300)</script>
+</body></html>`
+
+func TestScrapeEndpoint_WaitValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	handler := api.NewScrapeHandler(scraperService)
+	router := gin.New()
+	router.GET("/scrape", handler.HandleScrape)
+	router.POST("/scrape", handler.HandleScrapePost)
+
+	for _, query := range []string{
+		"wait=eventually",
+		"wait=selector",
+		"wait=function",
+		"wait=delay&waitMs=soon",
+		"wait=networkIdle&waitTimeoutMs=0",
+		"wait=delay&waitMs=600000",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/scrape?url=https://example.com&"+query, nil))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_wait") {
+			t.Errorf("%q: expected 400 invalid_wait, got %d: %s", query, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/scrape", bytes.NewBufferString(`{"url": "https://example.com", "wait": {"strategy": "function"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_wait") {
+		t.Errorf("Expected 400 invalid_wait for a function wait without a script, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestScrapeEndpoint_WaitParametersInferStrategy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(latePage))
+	}))
+	defer site.Close()
+
+	scraperService := services.NewScraperService(newLocalConfig())
+	defer scraperService.Close()
+
+	router := gin.New()
+	router.GET("/scrape", api.NewScrapeHandler(scraperService).HandleScrape)
+
+	// Without wait= the strategy follows from the parameter given
+	for _, query := range []string{
+		"waitMs=300",
+		"waitSelector=%23ready",
+		"waitScript=document.getElementById(%22ready%22)",
+		"waitTimeoutMs=1000",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/scrape?mode=static&url="+site.URL+"/&"+query, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%q: expected 200, got %d: %s", query, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestScrape_WaitStrategies(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(latePage))
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	tests := []struct {
+		name string
+		wait models.WaitOptions
+	}{
+		{"selector", models.WaitOptions{Strategy: models.WaitSelector, Selector: "#ready"}},
+		{"function", models.WaitOptions{Strategy: models.WaitFunction, Script: `document.getElementById("ready") !== null`}},
+		{"domStable", models.WaitOptions{Strategy: models.WaitDOMStable, Ms: 500}},
+		{"delay", models.WaitOptions{Strategy: models.WaitDelay, Ms: 800}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+				Mode:  models.RenderModeBrowser,
+				Cache: models.CacheBypass,
+				Wait:  &tt.wait,
+			})
+			if err != nil {
+				t.Fatalf("Scrape failed: %v", err)
+			}
+			if result.Mode != models.RenderModeBrowser {
+				t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+			}
+			if !strings.Contains(result.Markdown, "Rendered late") {
+				t.Errorf("Expected content rendered after the wait, got:\n%s", result.Markdown)
+			}
+		})
+	}
+}
+
+func TestScrape_UnmetWaitKeepsPage(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(latePage))
+	}))
+	defer site.Close()
+
+	cfg := newLocalConfig()
+	cfg.ChromedpTimeoutSeconds = 10
+	scraperService := services.NewScraperService(cfg)
+	defer scraperService.Close()
+
+	result, err := scraperService.Scrape(context.Background(), site.URL+"/", models.ScrapeOptions{
+		Mode: models.RenderModeBrowser,
+		Wait: &models.WaitOptions{Strategy: models.WaitSelector, Selector: "#never", TimeoutMs: 500},
+	})
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if result.Mode != models.RenderModeBrowser {
+		t.Skipf("Headless Chrome unavailable: %v", result.Warnings)
+	}
+	if !strings.Contains(strings.Join(result.Warnings, "\n"), "Wait condition not met") {
+		t.Errorf("Expected a warning for the unmet wait, got warnings %v", result.Warnings)
+	}
+	if !strings.Contains(result.Markdown, "Rendered late") {
+		t.Errorf("Expected the page to be captured after the timeout, got:\n%s", result.Markdown)
+	}
+}